@@ -0,0 +1,143 @@
+package flyway
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Command is a flyway command run by the container
+type Command string
+
+const (
+	// CommandMigrate migrates the schema to the latest version
+	CommandMigrate Command = "migrate"
+	// CommandInfo prints the details and status information about all the migrations
+	CommandInfo Command = "info"
+	// CommandValidate validates the applied migrations against the available ones
+	CommandValidate Command = "validate"
+	// CommandClean drops all the objects in the configured schemas, it enables FLYWAY_CLEAN_DISABLED=false
+	// unless the setting is provided explicitly. In exec mode, clean is only enabled when it is executed.
+	CommandClean Command = "clean"
+	// CommandRepair repairs the schema history table
+	CommandRepair Command = "repair"
+	// CommandBaseline baselines an existing database, excluding all the migrations up to and including the
+	// baseline version, see WithBaselineVersion
+	CommandBaseline Command = "baseline"
+	// CommandCheck produces reports to increase confidence in the migrations. It requires a flyway edition which
+	// supports it, see WithLicenseKey, and the reports to produce, e.g. WithCommandArgs("-changes", "-drift").
+	CommandCheck Command = "check"
+	// CommandUndo undoes the most recently applied versioned migration. It requires a flyway edition which
+	// supports it, see WithLicenseKey.
+	CommandUndo Command = "undo"
+)
+
+// command describes how the success of a flyway command is detected
+type command struct {
+	// logged matches the line flyway logs once the command succeeded, empty if the exit code is enough
+	logged string
+	// succeeded checks the json result of the command, nil if the exit code is enough
+	succeeded func(*Output) error
+	// env holds the settings the command needs to run, unless they are provided explicitly
+	env map[string]string
+}
+
+var commands = map[Command]command{
+	CommandMigrate: {
+		// nothing is applied when the schema is already up to date, which is a success too
+		logged: `Successfully applied \d+ migrations? to schema|Schema .+ is up to date\. No migration necessary\.`,
+		succeeded: func(output *Output) error {
+			if output.Migrate == nil || !output.Migrate.Success {
+				return errors.New("migrate was not successful")
+			}
+			return nil
+		},
+	},
+	CommandInfo: {
+		logged: `Schema version:`,
+	},
+	CommandValidate: {
+		logged: `Successfully validated \d+ migrations?`,
+		succeeded: func(output *Output) error {
+			if output.Validate == nil || !output.Validate.ValidationSuccessful {
+				return errors.New("validate was not successful")
+			}
+			return nil
+		},
+	},
+	CommandClean: {
+		logged: `Successfully (cleaned|dropped) schema`,
+		env: map[string]string{
+			flywayEnvCleanDisabledKey: "false",
+		},
+	},
+	CommandRepair: {
+		logged: `Successfully repaired schema history table`,
+	},
+	CommandBaseline: {
+		logged: `Successfully baselined schema with version`,
+		succeeded: func(output *Output) error {
+			if output.Baseline == nil || !output.Baseline.SuccessfullyBaselined {
+				return errors.New("baseline was not successful")
+			}
+			return nil
+		},
+	},
+	CommandCheck: {},
+	CommandUndo: {
+		logged: `Successfully undid \d+ migrations? to schema`,
+	},
+}
+
+// WithCommands sets the flyway commands run by the container, in order. Defaults to migrate then info.
+func WithCommands(cmds ...Command) Option {
+	return func(o *options) error {
+		if len(cmds) == 0 {
+			return errors.New("at least one command must be provided")
+		}
+
+		for _, cmd := range cmds {
+			if _, ok := commands[cmd]; !ok {
+				return fmt.Errorf("unsupported command: %q", cmd)
+			}
+		}
+
+		o.commands = cmds
+		o.notExecOptions = append(o.notExecOptions, "flyway.WithCommands()")
+		return nil
+	}
+}
+
+// WithCommandArgs passes extra command line arguments to flyway, e.g. the -changes or -drift reports of the check
+// command. In exec mode, they are passed to every command executed.
+func WithCommandArgs(args ...string) Option {
+	return func(o *options) error {
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "-") || len(arg) == 1 {
+				return fmt.Errorf("invalid command argument %q: flyway arguments start with -", arg)
+			}
+			if strings.HasPrefix(arg, outputTypeFlag) {
+				return fmt.Errorf("invalid command argument %q: please use flyway.WithOutputType()", arg)
+			}
+		}
+
+		o.args = append(o.args, args...)
+		return nil
+	}
+}
+
+// checkCommandsOutput checks the json output of the commands
+func checkCommandsOutput(cmds []Command, output *Output) error {
+	if output.Error != nil {
+		return output.Error
+	}
+
+	for _, cmd := range cmds {
+		if succeeded := commands[cmd].succeeded; succeeded != nil {
+			if err := succeeded(output); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
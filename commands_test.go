@@ -0,0 +1,68 @@
+package flyway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlyway_commandsLogged(t *testing.T) {
+	logged := map[Command]string{
+		CommandMigrate:  `Successfully applied 2 migrations to schema "public", now at version v1.2 (execution time 00:00.031s)`,
+		CommandInfo:     `Schema version: 1.2`,
+		CommandValidate: `Successfully validated 1 migration (execution time 00:00.017s)`,
+		CommandClean:    `Successfully cleaned schema "public" (execution time 00:00.021s)`,
+		CommandRepair:   `Successfully repaired schema history table "public"."schema_version" (execution time 00:00.035s).`,
+		CommandBaseline: `Successfully baselined schema with version: 1`,
+		CommandUndo:     `Successfully undid 1 migration to schema "public", now at version v1.1 (execution time 00:00.012s)`,
+	}
+
+	for cmd, line := range logged {
+		require.NotEmpty(t, commands[cmd].logged, "missing success line for %s", cmd)
+		require.Regexp(t, commands[cmd].logged, line, "unexpected success line for %s", cmd)
+	}
+}
+
+func TestFlyway_commandsLoggedUpToDate(t *testing.T) {
+	require.Regexp(t, commands[CommandMigrate].logged, `Schema "public" is up to date. No migration necessary.`)
+}
+
+func TestFlyway_optionsCmd(t *testing.T) {
+	settings := defaultOptions()
+	require.Equal(t, []string{"migrate", "info"}, settings.cmd())
+
+	require.NoError(t, WithCommands(CommandClean, CommandMigrate)(&settings))
+	require.NoError(t, WithOutputType(OutputTypeJSON)(&settings))
+	require.Equal(t, []string{"-outputType=json", "clean", "migrate"}, settings.cmd())
+
+	require.NoError(t, WithCommands(CommandCheck)(&settings))
+	require.NoError(t, WithCommandArgs("-changes", "-drift")(&settings))
+	require.Equal(t, []string{"-outputType=json", "-changes", "-drift", "check"}, settings.cmd())
+}
+
+func TestFlyway_withCommandArgsInvalid(t *testing.T) {
+	settings := defaultOptions()
+	require.Error(t, WithCommandArgs("changes")(&settings), "expected arguments to start with -")
+	require.Error(t, WithCommandArgs("-")(&settings), "expected an argument name")
+	require.Error(t, WithCommandArgs("-outputType=json")(&settings), "expected the output type option to be used")
+	require.Empty(t, settings.args)
+}
+
+func TestFlyway_checkCommandsOutput(t *testing.T) {
+	cmds := []Command{CommandMigrate, CommandValidate}
+
+	err := checkCommandsOutput(cmds, &Output{
+		Migrate:  &MigrateResult{Success: true},
+		Validate: &ValidateResult{ValidationSuccessful: true},
+	})
+	require.NoError(t, err)
+
+	err = checkCommandsOutput(cmds, &Output{
+		Migrate:  &MigrateResult{Success: true},
+		Validate: &ValidateResult{ValidationSuccessful: false},
+	})
+	require.EqualError(t, err, "validate was not successful")
+
+	err = checkCommandsOutput(cmds, &Output{Error: &ErrorOutput{ErrorCode: "FAULT", Message: "boom"}})
+	require.EqualError(t, err, "flyway error FAULT: boom")
+}
@@ -0,0 +1,159 @@
+package flyway
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var (
+	// failedMigrationRegexp matches the line flyway logs when a migration fails, e.g.
+	// ERROR: Migration V1.2__alter_table_stuff.sql failed
+	failedMigrationRegexp = regexp.MustCompile(`^(?:ERROR: )?Migration (\S+) failed$`)
+	// errorDetailRegexp matches the details flyway logs below a failure, e.g. SQL State  : 42601
+	errorDetailRegexp = regexp.MustCompile(`^(SQL State|Error Code|Message|Location|Line|Statement)\s*: ?(.*)$`)
+)
+
+// MigrationError is returned when the flyway container exits with a non-zero code, e.g. when a migration fails.
+// The details are parsed from the flyway output and are empty when flyway does not report them, e.g. Script is
+// empty when the failure is not caused by a migration. In json mode it wraps the ErrorOutput reported by flyway.
+type MigrationError struct {
+	ExitCode int
+	// Script is the name of the failing migration, e.g. V1.2__alter_table_stuff.sql
+	Script string
+	// SQLState is the SQL state reported by the database
+	SQLState string
+	// ErrorCode is the vendor specific error code reported by the database
+	ErrorCode int
+	// Message is the error reported by flyway or the database
+	Message string
+	// Line is the line of the failing statement in the migration
+	Line int
+	// Statement is the failing statement of the migration
+	Statement string
+	// Logs is the full output of the container
+	Logs string
+
+	output *ErrorOutput
+}
+
+// Error implements the error interface
+func (e *MigrationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "flyway exited with code %d", e.ExitCode)
+	if e.Script != "" {
+		fmt.Fprintf(&b, ": migration %s failed", e.Script)
+		if e.Line > 0 {
+			fmt.Fprintf(&b, " at line %d", e.Line)
+		}
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.SQLState != "" {
+		fmt.Fprintf(&b, " (SQL state %s, error code %d)", e.SQLState, e.ErrorCode)
+	}
+	return b.String()
+}
+
+// Unwrap returns the error reported by flyway in json mode, nil otherwise
+func (e *MigrationError) Unwrap() error {
+	if e.output == nil {
+		return nil
+	}
+	return e.output
+}
+
+// newMigrationError returns the error of a container which exited with a non-zero code, with the details found in
+// its logs
+func newMigrationError(ctx context.Context, target wait.StrategyTarget, exitCode int) *MigrationError {
+	migrationErr := &MigrationError{ExitCode: exitCode}
+
+	logs, err := target.Logs(ctx)
+	if err != nil {
+		migrationErr.Message = fmt.Sprintf("failed to get container logs: %s", err)
+		return migrationErr
+	}
+	defer logs.Close()
+
+	content, err := io.ReadAll(logs)
+	if err != nil {
+		migrationErr.Message = fmt.Sprintf("failed to read container logs: %s", err)
+		return migrationErr
+	}
+
+	migrationErr.Logs = string(content)
+	migrationErr.parse()
+	return migrationErr
+}
+
+// parse fills in the details of the error from the logs, either from the json error or from the text output
+func (e *MigrationError) parse() {
+	if output, err := parseOutput(strings.NewReader(e.Logs)); err == nil && output.Error != nil {
+		e.output = output.Error
+		e.SQLState = output.Error.SQLState
+		e.ErrorCode = output.Error.SQLErrorCode
+		e.Line = output.Error.LineNumber
+		if output.Error.Path != "" {
+			e.Script = path.Base(output.Error.Path)
+		}
+
+		// the message holds the same details as the text output
+		e.parseText(output.Error.Message)
+		if e.Message == "" {
+			e.Message = output.Error.Message
+		}
+		return
+	}
+
+	e.parseText(e.Logs)
+}
+
+// parseText fills in the details of the error logged by flyway in text mode, without overriding the ones already
+// known
+func (e *MigrationError) parseText(text string) {
+	var firstError, key string
+	details := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if matches := failedMigrationRegexp.FindStringSubmatch(line); matches != nil {
+			e.Script = valueOrDefault(e.Script, matches[1])
+			continue
+		}
+		if matches := errorDetailRegexp.FindStringSubmatch(line); matches != nil {
+			key = matches[1]
+			details[key] = matches[2]
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(line, "---"):
+			key = ""
+		case key == "Message" || key == "Statement":
+			// multi-line details are continued until the next detail or blank line
+			details[key] += "\n" + line
+		case firstError == "" && strings.HasPrefix(line, "ERROR: "):
+			firstError = strings.TrimPrefix(line, "ERROR: ")
+		}
+	}
+
+	e.SQLState = valueOrDefault(e.SQLState, details["SQL State"])
+	e.Message = valueOrDefault(e.Message, strings.TrimSpace(valueOrDefault(details["Message"], firstError)))
+	e.Statement = valueOrDefault(e.Statement, strings.TrimSpace(details["Statement"]))
+	if code, err := strconv.Atoi(details["Error Code"]); err == nil && e.ErrorCode == 0 {
+		e.ErrorCode = code
+	}
+	if line, err := strconv.Atoi(details["Line"]); err == nil && e.Line == 0 {
+		e.Line = line
+	}
+}
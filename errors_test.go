@@ -0,0 +1,79 @@
+package flyway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const failedMigrationOutput = `Flyway Community Edition 10.15.0 by Redgate
+
+See release notes here: https://rd.gt/416ObMi
+Database: jdbc:postgresql://pgdb:5432/test_db (PostgreSQL 16.3)
+Successfully validated 2 migrations (execution time 00:00.017s)
+Current version of schema "public": 1.1
+Migrating schema "public" to version "1.2 - alter table stuff"
+ERROR: Migration of schema "public" to version "1.2 - alter table stuff" failed! Changes successfully rolled back.
+ERROR: Migration V1.2__alter_table_stuff.sql failed
+-----------------------------------------------
+SQL State  : 42601
+Error Code : 0
+Message    : ERROR: syntax error at or near "TABL"
+  Position: 7
+Location   : /flyway/sql/V1.2__alter_table_stuff.sql (/flyway/sql/V1.2__alter_table_stuff.sql)
+Line       : 1
+Statement  : ALTER TABL stuff
+ADD COLUMN created_timestamp TIMESTAMP
+
+`
+
+func TestFlyway_migrationErrorText(t *testing.T) {
+	migrationErr := &MigrationError{ExitCode: 1, Logs: failedMigrationOutput}
+	migrationErr.parse()
+
+	require.Equal(t, "V1.2__alter_table_stuff.sql", migrationErr.Script)
+	require.Equal(t, "42601", migrationErr.SQLState)
+	require.Equal(t, 0, migrationErr.ErrorCode)
+	require.Equal(t, "ERROR: syntax error at or near \"TABL\"\n  Position: 7", migrationErr.Message)
+	require.Equal(t, 1, migrationErr.Line)
+	require.Equal(t, "ALTER TABL stuff\nADD COLUMN created_timestamp TIMESTAMP", migrationErr.Statement)
+	require.Nil(t, migrationErr.Unwrap())
+	require.Contains(t, migrationErr.Error(), "flyway exited with code 1: migration V1.2__alter_table_stuff.sql failed at line 1: ERROR: syntax error")
+	require.Contains(t, migrationErr.Error(), "(SQL state 42601, error code 0)")
+}
+
+func TestFlyway_migrationErrorTextWithoutMigration(t *testing.T) {
+	migrationErr := &MigrationError{ExitCode: 1, Logs: `Flyway Community Edition 10.15.0 by Redgate
+ERROR: Found non-empty schema(s) "public" but no schema history table. Use baseline() or set baselineOnMigrate to true to initialize the schema history table.
+`}
+	migrationErr.parse()
+
+	require.Empty(t, migrationErr.Script)
+	require.Empty(t, migrationErr.SQLState)
+	require.Equal(t, `flyway exited with code 1: Found non-empty schema(s) "public" but no schema history table. Use baseline() or set baselineOnMigrate to true to initialize the schema history table.`, migrationErr.Error())
+}
+
+func TestFlyway_migrationErrorJSON(t *testing.T) {
+	migrationErr := &MigrationError{ExitCode: 1, Logs: `{
+  "error" : {
+    "errorCode" : "FAULT",
+    "sqlState" : "42601",
+    "sqlErrorCode" : 0,
+    "message" : "Migration V1.2__alter_table_stuff.sql failed\n-----------------------------------------------\nSQL State  : 42601\nError Code : 0\nMessage    : ERROR: syntax error at or near \"TABL\"\nLocation   : /flyway/sql/V1.2__alter_table_stuff.sql (/flyway/sql/V1.2__alter_table_stuff.sql)\nLine       : 1\nStatement  : ALTER TABL stuff\n",
+    "lineNumber" : 1,
+    "path" : "/flyway/sql/V1.2__alter_table_stuff.sql"
+  }
+}`}
+	migrationErr.parse()
+
+	require.Equal(t, "V1.2__alter_table_stuff.sql", migrationErr.Script)
+	require.Equal(t, "42601", migrationErr.SQLState)
+	require.Equal(t, `ERROR: syntax error at or near "TABL"`, migrationErr.Message)
+	require.Equal(t, 1, migrationErr.Line)
+	require.Equal(t, "ALTER TABL stuff", migrationErr.Statement)
+
+	var output *ErrorOutput
+	require.True(t, errors.As(migrationErr, &output))
+	require.Equal(t, "FAULT", output.ErrorCode)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CyberOwlTeam/flyway"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/clickhouse"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+const (
+	clickhouseImage    = "clickhouse/clickhouse-server:23.3.8.21-alpine"
+	clickhouseSrvName  = "clickhouse"
+	clickhouseDBName   = "test_db"
+	clickhouseUsername = "test-user"
+	clickhousePassword = "test-password"
+)
+
+func TestFlyway_clickhouseTarget(t *testing.T) {
+	ctx := context.Background()
+
+	// Create a new docker network
+	nw, err := network.New(ctx)
+	require.NoError(t, err, "failed creating network")
+
+	// Create a new ClickHouseContainer
+	dbContainer, err := clickhouse.RunContainer(ctx,
+		network.WithNetwork([]string{clickhouseSrvName}, nw),
+		testcontainers.WithImage(clickhouseImage),
+		clickhouse.WithDatabase(clickhouseDBName),
+		clickhouse.WithUsername(clickhouseUsername),
+		clickhouse.WithPassword(clickhousePassword),
+	)
+	require.NoError(t, err, "failed creating clickhouse container")
+
+	// Create a Flyway container wired to the ClickHouseContainer and run SQL migration
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithClickHouse(dbContainer),
+		flyway.WithTimeout(1*time.Minute),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = dbContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate clickhouse container")
+	})
+
+	// Inspect the migrations applied by flyway
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get flyway report")
+	require.Equal(t, "2", report.SchemaVersion)
+	require.Len(t, report.Applied(), 2)
+}
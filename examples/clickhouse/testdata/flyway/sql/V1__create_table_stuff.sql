@@ -0,0 +1,5 @@
+CREATE TABLE stuff
+(
+    id   UUID DEFAULT generateUUIDv4(),
+    name String
+) ENGINE = MergeTree ORDER BY id;
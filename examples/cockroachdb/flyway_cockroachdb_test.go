@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CyberOwlTeam/flyway"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	cockroachdbImage   = "cockroachdb/cockroach:v23.2.6"
+	cockroachdbSrvName = "cockroachdb"
+)
+
+func TestFlyway_cockroachdbTarget(t *testing.T) {
+	ctx := context.Background()
+
+	// Create a new docker network
+	nw, err := network.New(ctx)
+	require.NoError(t, err, "failed creating network")
+
+	// Create a new insecure CockroachDB node, the cockroachdb module isn't available for this testcontainers version
+	dbContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        cockroachdbImage,
+			Cmd:          []string{"start-single-node", "--insecure"},
+			ExposedPorts: []string{"26257/tcp", "8080/tcp"},
+			Networks:     []string{nw.Name},
+			NetworkAliases: map[string][]string{
+				nw.Name: {cockroachdbSrvName},
+			},
+			WaitingFor: wait.ForHTTP("/health").WithPort("8080/tcp").WithStartupTimeout(1 * time.Minute),
+		},
+		Started: true,
+	})
+	require.NoError(t, err, "failed creating cockroachdb container")
+
+	// Create a Flyway container wired to the CockroachDB node and run SQL migration
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithCockroachDB(dbContainer),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = dbContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate cockroachdb container")
+	})
+
+	// Inspect the migrations applied by flyway
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get flyway report")
+	require.Equal(t, "2", report.SchemaVersion)
+	require.Len(t, report.Applied(), 2)
+}
@@ -0,0 +1,5 @@
+CREATE TABLE stuff
+(
+    id   UUID         NOT NULL PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE stuff ADD COLUMN created_timestamp TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CyberOwlTeam/flyway"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mssql"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+const (
+	mssqlImage    = "mcr.microsoft.com/mssql/server:2022-CU10-ubuntu-22.04"
+	mssqlSrvName  = "mssql"
+	mssqlPassword = "Str0ng!Passw0rd"
+)
+
+func TestFlyway_mssqlTarget(t *testing.T) {
+	ctx := context.Background()
+
+	// Create a new docker network
+	nw, err := network.New(ctx)
+	require.NoError(t, err, "failed creating network")
+
+	// Create a new MSSQLServerContainer
+	dbContainer, err := mssql.RunContainer(ctx,
+		network.WithNetwork([]string{mssqlSrvName}, nw),
+		testcontainers.WithImage(mssqlImage),
+		mssql.WithAcceptEULA(),
+		mssql.WithPassword(mssqlPassword),
+	)
+	require.NoError(t, err, "failed creating mssql container")
+
+	// Create a Flyway container wired to the MSSQLServerContainer and run SQL migration
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithMSSQL(dbContainer),
+		flyway.WithTimeout(1*time.Minute),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = dbContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate mssql container")
+	})
+
+	// Inspect the migrations applied by flyway
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get flyway report")
+	require.Equal(t, "2", report.SchemaVersion)
+	require.Len(t, report.Applied(), 2)
+}
@@ -0,0 +1,5 @@
+CREATE TABLE stuff
+(
+    id   UNIQUEIDENTIFIER NOT NULL PRIMARY KEY DEFAULT NEWID(),
+    name NVARCHAR(255)    NOT NULL
+);
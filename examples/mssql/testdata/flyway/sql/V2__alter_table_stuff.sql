@@ -0,0 +1 @@
+ALTER TABLE stuff ADD created_timestamp DATETIME2 NOT NULL DEFAULT SYSUTCDATETIME();
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CyberOwlTeam/flyway"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mariadb"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	mariadbDBVersion = "11.4"
+	mariadbSrvName   = "mariadb"
+)
+
+// mariadbContainer represents an abstration of MariaDBContainer.
+type mariadbContainer struct {
+	*mariadb.MariaDBContainer
+}
+
+func TestFlyway_mariadbTarget(t *testing.T) {
+	ctx := context.Background()
+
+	// Create a new docker network
+	nw, err := network.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	// Create a new MariaDBContainer
+	dbContainer, err := createTestMariaDBContainer(ctx, nw)
+	require.NoError(t, err, "failed creating mariadb container")
+
+	// Create a Flyway container wired to the MariaDBContainer and run SQL migration
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithMariaDB(dbContainer),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = dbContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate mariadb container")
+	})
+
+	// Execute some queries on database
+	err = execSampleQuery(ctx, dbContainer)
+	require.NoError(t, err, "failed to execute query")
+}
+
+// getExternalURL returns the external URL to [mariadbContainer].
+func (c *mariadbContainer) getExternalURL(ctx context.Context) (string, error) {
+	url, err := c.ConnectionString(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s?parseTime=true", url), nil
+}
+
+// createTestMariaDBContainer instantiates and runs a MariaDB container.
+func createTestMariaDBContainer(ctx context.Context, nw *testcontainers.DockerNetwork) (*mariadbContainer, error) {
+	dbContainer, err := mariadb.RunContainer(ctx,
+		network.WithNetwork([]string{mariadbSrvName}, nw),
+		testcontainers.WithImage(fmt.Sprintf("mariadb:%s", mariadbDBVersion)),
+		mariadb.WithDatabase(mysqlDBName),
+		mariadb.WithUsername(mysqlDBUsername),
+		mariadb.WithPassword(mysqlDBPassword),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("ready for connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mariadbContainer{
+		dbContainer,
+	}, nil
+}
@@ -30,6 +30,11 @@ const (
 	mysqlDBPassword = "password"
 )
 
+// externalURLContainer is a database container reachable from the tests.
+type externalURLContainer interface {
+	getExternalURL(ctx context.Context) (string, error)
+}
+
 // mysqlContainer represents an abstration of MySQLContainer.
 type mysqlContainer struct {
 	*mysql.MySQLContainer
@@ -77,8 +82,41 @@ func TestFlyway_mysql(t *testing.T) {
 	require.Equal(t, 0, state.ExitCode, "container exit code was not as expected: migration failed")
 }
 
+func TestFlyway_mysqlTarget(t *testing.T) {
+	ctx := context.Background()
+
+	// Create a new docker network
+	nw, err := network.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	// Create a new MySQLContainer
+	dbContainer, err := createTestMySQLContainer(ctx, nw)
+	require.NoError(t, err, "failed creating mysql container")
+
+	// Create a Flyway container wired to the MySQLContainer and run SQL migration
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithMySQL(dbContainer),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = dbContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate mysql container")
+	})
+
+	// Execute some queries on database
+	err = execSampleQuery(ctx, dbContainer)
+	require.NoError(t, err, "failed to execute query")
+}
+
 // execSampleQuery executes queries for dbContainer.
-func execSampleQuery(ctx context.Context, dbContainer *mysqlContainer) error {
+func execSampleQuery(ctx context.Context, dbContainer externalURLContainer) error {
 	uri, err := dbContainer.getExternalURL(ctx)
 	if err != nil {
 		return fmt.Errorf("get external URL: %w", err)
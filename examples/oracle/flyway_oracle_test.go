@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CyberOwlTeam/flyway"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	oracleImage       = "gvenzl/oracle-free:23-slim-faststart"
+	oracleSrvName     = "oracle"
+	oraclePassword    = "test-password"
+	oracleAppUser     = "test_user"
+	oracleAppPassword = "test-password"
+)
+
+func TestFlyway_oracleTarget(t *testing.T) {
+	ctx := context.Background()
+
+	// Create a new docker network
+	nw, err := network.New(ctx)
+	require.NoError(t, err, "failed creating network")
+
+	// Create a new Oracle Free container, there is no testcontainers module for it
+	dbContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        oracleImage,
+			ExposedPorts: []string{"1521/tcp"},
+			Env: map[string]string{
+				"ORACLE_PASSWORD":   oraclePassword,
+				"APP_USER":          oracleAppUser,
+				"APP_USER_PASSWORD": oracleAppPassword,
+			},
+			Networks: []string{nw.Name},
+			NetworkAliases: map[string][]string{
+				nw.Name: {oracleSrvName},
+			},
+			WaitingFor: wait.ForLog("DATABASE IS READY TO USE!").WithStartupTimeout(5 * time.Minute),
+		},
+		Started: true,
+	})
+	require.NoError(t, err, "failed creating oracle container")
+
+	// Create a Flyway container wired to the Oracle container and run SQL migration
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithOracle(dbContainer),
+		flyway.WithTimeout(1*time.Minute),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = dbContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate oracle container")
+	})
+
+	// Inspect the migrations applied by flyway
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get flyway report")
+	require.Equal(t, "2", report.SchemaVersion)
+	require.Len(t, report.Applied(), 2)
+}
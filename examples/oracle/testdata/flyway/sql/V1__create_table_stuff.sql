@@ -0,0 +1,5 @@
+CREATE TABLE stuff
+(
+    id   RAW(16)       DEFAULT SYS_GUID() NOT NULL PRIMARY KEY,
+    name VARCHAR2(255) NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE stuff ADD created_timestamp TIMESTAMP DEFAULT SYSTIMESTAMP NOT NULL;
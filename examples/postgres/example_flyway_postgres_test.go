@@ -33,7 +33,7 @@ func ExampleFlyway_postgres() {
 		flyway.WithPassword(defaultPostgresDbPassword),
 		flyway.WithConnectRetries(3),
 		flyway.WithTable("my_schema_history"),
-		flyway.WithGroup("my_group"),
+		flyway.WithGroup(true),
 		flyway.WithTimeout(1*time.Minute),
 		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
 	)
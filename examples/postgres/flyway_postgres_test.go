@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"database/sql"
+	"embed"
 	"fmt"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/CyberOwlTeam/flyway"
+	"github.com/CyberOwlTeam/flyway/flywaytest"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/go-connections/nat"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
@@ -30,6 +33,9 @@ const (
 	defaultPostgresDbPassword = "postgres"
 )
 
+//go:embed testdata/flyway/sql
+var migrationsFS embed.FS
+
 func TestFlyway_postgres(t *testing.T) {
 	// given
 	ctx := context.Background()
@@ -61,10 +67,789 @@ func TestFlyway_postgres(t *testing.T) {
 
 	requireQuery(t, ctx, postgresContainer)
 
-	state, err := flywayContainer.State(ctx)
-	require.NoError(t, err, "failed to get container state")
-	require.Emptyf(t, state.Error, "failed to get container state")
-	require.Equal(t, 0, state.ExitCode, "container exit code was not as expected: migration failed")
+	state, err := flywayContainer.State(ctx)
+	require.NoError(t, err, "failed to get container state")
+	require.Emptyf(t, state.Error, "failed to get container state")
+	require.Equal(t, 0, state.ExitCode, "container exit code was not as expected: migration failed")
+
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get migrations report")
+	require.Equal(t, "2.2", report.SchemaVersion)
+	require.Len(t, report.Applied(), 3, "unexpected number of applied migrations")
+}
+
+func TestFlyway_postgresMigrationsFS(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithMigrationsFS(migrationsFS, "testdata/flyway/sql"),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	requireQuery(t, ctx, postgresContainer)
+}
+
+func TestFlyway_postgresInlineMigrations(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []flyway.Migration
+		applied    int
+	}{
+		{
+			name: "single migration",
+			migrations: []flyway.Migration{
+				{Version: "1", Description: "create stuff", SQL: "CREATE TABLE stuff (name TEXT NOT NULL);"},
+			},
+			applied: 1,
+		},
+		{
+			name: "several migrations",
+			migrations: []flyway.Migration{
+				{Version: "1", Description: "create stuff", SQL: "CREATE TABLE stuff (name TEXT NOT NULL);"},
+				{Version: "1.1", Description: "alter stuff", SQL: "ALTER TABLE stuff ADD COLUMN size INT;"},
+			},
+			applied: 2,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			// given
+			ctx := context.Background()
+			nw, err := tcnetwork.New(context.Background())
+			require.NoError(tt, err, "failed creating network")
+
+			postgresContainer, err := createTestPostgresContainer(ctx, nw)
+			require.NoError(tt, err, "failed creating postgres container")
+
+			// when
+			flywayContainer, err := flyway.RunContainer(ctx,
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				tcnetwork.WithNetwork([]string{"flyway"}, nw),
+				flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithInlineMigrations(testCase.migrations...),
+			)
+			require.NoError(tt, err, "failed to run container")
+
+			// then
+			tt.Cleanup(func() {
+				err := flywayContainer.Terminate(ctx)
+				require.NoError(tt, err, "failed to terminate flyway container")
+
+				err = postgresContainer.Terminate(ctx)
+				require.NoError(tt, err, "failed to terminate postgres container")
+			})
+
+			report, err := flywayContainer.Report(ctx)
+			require.NoError(tt, err, "failed to get migrations report")
+			require.Len(tt, report.Applied(), testCase.applied, "unexpected number of applied migrations")
+		})
+	}
+}
+
+func TestFlyway_postgresMigrationsLocations(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+		flyway.WithMigrationsLocation(filepath.Join("testdata", "flyway", "service"), "/flyway/service"),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get migrations report")
+	require.Equal(t, "3", report.SchemaVersion)
+	require.Len(t, report.Applied(), 4, "unexpected number of applied migrations")
+}
+
+func TestFlyway_postgresPlaceholders(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithInlineMigrations(
+			flyway.Migration{Version: "1", Description: "create stuff", SQL: "CREATE TABLE ${table_name} (name TEXT NOT NULL);"},
+		),
+		flyway.WithPlaceholders(map[string]string{"table_name": "stuff"}),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	postgresUrl, err := postgresContainer.getExternalUrl(ctx)
+	require.NoError(t, err, "failed getting external postgres url")
+
+	db, err := sql.Open("postgres", postgresUrl)
+	require.NoError(t, err, "failed opening sql connection to postgres")
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "INSERT INTO stuff (name) VALUES($1)", "test")
+	require.NoError(t, err, "failed to insert into the table named by the placeholder")
+}
+
+func TestFlyway_postgresSchemas(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithInlineMigrations(
+			flyway.Migration{Version: "1", Description: "create stuff", SQL: "CREATE TABLE inventory.stuff (name TEXT NOT NULL);"},
+			flyway.Migration{Version: "2", Description: "create orders", SQL: "CREATE TABLE billing.orders (name TEXT NOT NULL);"},
+		),
+		flyway.WithSchemas("inventory", "billing"),
+		flyway.WithDefaultSchema("billing"),
+		flyway.WithCreateSchemas(true),
+		flyway.WithOutputType(flyway.OutputTypeJSON),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get migrations report")
+	require.Equal(t, "billing", report.HistorySchema, "unexpected schema history table location")
+	require.Len(t, report.Applied(), 2, "unexpected number of applied migrations")
+}
+
+func TestFlyway_postgresTarget(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	requireQuery(t, ctx, postgresContainer)
+}
+
+func TestFlyway_postgresTargetWithoutNetwork(t *testing.T) {
+	// given
+	ctx := context.Background()
+	postgresContainer, err := tcpostgres.RunContainer(ctx,
+		testcontainers.WithImage(fmt.Sprintf("postgres:%s", defaultPostgresDbVersion)),
+		tcpostgres.WithDatabase(defaultPostgresDbName),
+		tcpostgres.WithUsername(defaultPostgresDbUsername),
+		tcpostgres.WithPassword(defaultPostgresDbPassword),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(10*time.Second)),
+	)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	flywayNetworks, err := flywayContainer.Networks(ctx)
+	require.NoError(t, err, "failed to get flyway container networks")
+	require.Len(t, flywayNetworks, 1)
+
+	postgresNetworks, err := postgresContainer.Networks(ctx)
+	require.NoError(t, err, "failed to get postgres container networks")
+	require.Contains(t, postgresNetworks, flywayNetworks[0], "expected the postgres container to be attached to the flyway network")
+
+	requireQuery(t, ctx, &flywayPostgresTestContainer{postgresContainer})
+
+	err = flywayContainer.Terminate(ctx)
+	require.NoError(t, err, "failed to terminate flyway container")
+
+	postgresNetworks, err = postgresContainer.Networks(ctx)
+	require.NoError(t, err, "failed to get postgres container networks")
+	require.NotContains(t, postgresNetworks, flywayNetworks[0], "expected the postgres container to be detached from the flyway network")
+}
+
+func TestFlyway_postgresHostAccess(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// the database is reached through its port mapped on the host, as a database running outside of docker
+	mappedPort, err := postgresContainer.MappedPort(ctx, nat.Port(fmt.Sprintf("%s/tcp", defaultPostgresPort)))
+	require.NoError(t, err, "failed getting postgres mapped port")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithHostAccess(flyway.HostAccessGateway),
+		flyway.WithDatabaseUrl(flyway.PostgresURL("localhost", mappedPort.Port(), defaultPostgresDbName).WithParam("sslmode", "disable").String()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	requireQuery(t, ctx, postgresContainer)
+}
+
+func TestFlyway_postgresMigrationError(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	// when
+	_, err = flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithInlineMigrations(
+			flyway.Migration{Version: "1", Description: "create stuff", SQL: "CREATE TABLE stuff (name TEXT NOT NULL);"},
+			flyway.Migration{Version: "2", Description: "alter stuff", SQL: "ALTER TABL stuff ADD COLUMN size INT;"},
+		),
+	)
+
+	// then
+	var migrationErr *flyway.MigrationError
+	require.ErrorAs(t, err, &migrationErr)
+	require.Equal(t, 1, migrationErr.ExitCode)
+	require.Equal(t, "V2__alter_stuff.sql", migrationErr.Script)
+	require.Equal(t, "42601", migrationErr.SQLState)
+	require.Contains(t, migrationErr.Message, "syntax error")
+	require.Contains(t, migrationErr.Statement, "ALTER TABL stuff")
+	require.Contains(t, migrationErr.Logs, "Successfully validated 2 migrations")
+}
+
+func TestFlyway_postgresNoContainerLeft(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	before := listFlywayContainers(t, ctx)
+
+	// when
+	tests := []struct {
+		name string
+		opts []testcontainers.ContainerCustomizer
+	}{
+		{
+			name: "failed migration",
+			opts: []testcontainers.ContainerCustomizer{
+				flyway.WithInlineMigrations(
+					flyway.Migration{Version: "1", Description: "create stuff", SQL: "CREATE TABL stuff (name TEXT NOT NULL);"},
+				),
+			},
+		},
+		{
+			name: "invalid request",
+			opts: []testcontainers.ContainerCustomizer{
+				flyway.WithDatabaseUrl("jdbc:postgres://pgdb:5432/test_db"),
+				flyway.WithInlineMigrations(
+					flyway.Migration{Version: "1", Description: "create stuff", SQL: "CREATE TABLE stuff (name TEXT NOT NULL);"},
+				),
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			opts := append([]testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithPostgres(postgresContainer),
+			}, testCase.opts...)
+
+			flywayContainer, err := flyway.RunContainer(ctx, opts...)
+			require.Error(tt, err, "expected error")
+			require.Nil(tt, flywayContainer, "expected nil container")
+
+			// then
+			require.ElementsMatch(tt, before, listFlywayContainers(tt, ctx), "expected no flyway container left behind")
+		})
+	}
+}
+
+func TestFlyway_postgresLogs(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithLogger(t),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	logs, err := flywayContainer.FlywayLogs(ctx)
+	require.NoError(t, err, "failed to get flyway logs")
+	require.Empty(t, logs.Level(flyway.LogLevelError))
+	require.NotEmpty(t, logs.Command(flyway.CommandMigrate))
+	require.NotEmpty(t, logs.Command(flyway.CommandInfo))
+}
+
+func TestFlyway_postgresMustMigrate(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	// when
+	flywaytest.MustMigrate(t,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+
+	// then
+	requireQuery(t, ctx, postgresContainer)
+}
+
+func TestFlyway_postgresExecMode(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithExecMode(),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	// when
+	migrate, err := flywayContainer.Migrate(ctx)
+	require.NoError(t, err, "failed to migrate")
+	require.Equal(t, 3, migrate.MigrationsExecuted)
+
+	validate, err := flywayContainer.Validate(ctx)
+	require.NoError(t, err, "failed to validate")
+	require.True(t, validate.ValidationSuccessful)
+
+	clean, err := flywayContainer.Clean(ctx)
+	require.NoError(t, err, "failed to clean")
+	require.Equal(t, []string{"public"}, clean.SchemasCleaned)
+
+	migrate, err = flywayContainer.Migrate(ctx)
+	require.NoError(t, err, "failed to migrate after clean")
+	require.Equal(t, 3, migrate.MigrationsExecuted)
+
+	// then
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get report")
+	require.Equal(t, "2.2", report.SchemaVersion)
+	require.Len(t, report.Applied(), 3)
+
+	requireQuery(t, ctx, postgresContainer)
+}
+
+func TestFlyway_postgresTargetVersion(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	// when
+	flywayContainer := flywaytest.MustMigrate(t,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithTarget("2.1"),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+
+	// then
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get report")
+	require.Equal(t, "2.1", report.SchemaVersion)
+	require.Len(t, report.Applied(), 2)
+}
+
+func TestFlyway_postgresMigrateStepwise(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	flywayContainer := flywaytest.MustRun(t,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+		flyway.WithExecMode(),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	)
+
+	postgresUrl, err := postgresContainer.getExternalUrl(ctx)
+	require.NoError(t, err, "failed getting external postgres url")
+
+	db, err := sql.Open("postgres", postgresUrl)
+	require.NoError(t, err, "failed opening sql connection to postgres")
+	defer db.Close()
+
+	// when
+	var versions []string
+	err = flywayContainer.MigrateStepwise(ctx, func(ctx context.Context, version string) error {
+		versions = append(versions, version)
+
+		switch version {
+		case "2.1":
+			// data inserted before the table is altered
+			_, err := db.ExecContext(ctx, "INSERT INTO stuff (name) VALUES($1)", "before 2.2")
+			return err
+		case "2.2":
+			var created time.Time
+			return db.QueryRowContext(ctx, "SELECT created_timestamp FROM stuff WHERE name = $1", "before 2.2").Scan(&created)
+		}
+		return nil
+	})
+
+	// then
+	require.NoError(t, err, "failed to migrate stepwise")
+	require.Equal(t, []string{"1", "2.1", "2.2"}, versions)
+}
+
+func TestFlyway_postgresUpgradeCompatibility(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+	t.Cleanup(func() {
+		err := postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	postgresUrl, err := postgresContainer.getExternalUrl(ctx)
+	require.NoError(t, err, "failed getting external postgres url")
+
+	db, err := sql.Open("postgres", postgresUrl)
+	require.NoError(t, err, "failed opening sql connection to postgres")
+	defer db.Close()
+
+	// when
+	var versions []string
+	flywaytest.UpgradeCompatibility(t, filepath.Join("testdata", flyway.DefaultMigrationsPath), flywaytest.UpgradeHooks{
+		AtVersion: func(t *testing.T, version string) {
+			versions = append(versions, version)
+			if version == "1" {
+				return // the stuff table is created by 2.1
+			}
+
+			_, err := db.ExecContext(ctx, "INSERT INTO stuff (name) VALUES($1)", "at "+version)
+			require.NoError(t, err, "failed to insert fixture")
+		},
+		AtHead: func(t *testing.T, version string) {
+			var created time.Time
+			err := db.QueryRowContext(ctx, "SELECT created_timestamp FROM stuff").Scan(&created)
+			if version == "1" {
+				require.ErrorIs(t, err, sql.ErrNoRows)
+				return
+			}
+			require.NoError(t, err, "fixture inserted at version %s did not survive", version)
+		},
+	},
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		flyway.WithPostgres(postgresContainer),
+	)
+
+	// then
+	require.Equal(t, []string{"1", "2.1", "2.2"}, versions)
+}
+
+func TestFlyway_postgresUpToDate(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	opts := []testcontainers.ContainerCustomizer{
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+	}
+
+	firstContainer, err := flyway.RunContainer(ctx, opts...)
+	require.NoError(t, err, "failed to run first container")
+
+	// when
+	secondContainer, err := flyway.RunContainer(ctx, opts...)
+	require.NoError(t, err, "failed to run second container, with no pending migrations")
+
+	// then
+	t.Cleanup(func() {
+		err := firstContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate first flyway container")
+
+		err = secondContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate second flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	report, err := secondContainer.Report(ctx)
+	require.NoError(t, err, "failed to get migrations report")
+	require.Len(t, report.Applied(), 3, "unexpected number of applied migrations")
+	require.Empty(t, report.Pending(), "unexpected pending migrations")
+}
+
+func TestFlyway_postgresJSONOutput(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+		flyway.WithOutputType(flyway.OutputTypeJSON),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	output, err := flywayContainer.Output(ctx)
+	require.NoError(t, err, "failed to decode flyway output")
+	require.Nil(t, output.Error, "unexpected flyway error")
+	require.NotNil(t, output.Migrate, "missing migrate result")
+	require.True(t, output.Migrate.Success, "migrate was not successful")
+	require.Equal(t, 3, output.Migrate.MigrationsExecuted)
+	require.NotNil(t, output.Info, "missing info result")
+	require.Equal(t, "2.2", output.Info.SchemaVersion)
+}
+
+func TestFlyway_postgresCommands(t *testing.T) {
+	// given
+	ctx := context.Background()
+	nw, err := tcnetwork.New(context.Background())
+	require.NoError(t, err, "failed creating network")
+
+	postgresContainer, err := createTestPostgresContainer(ctx, nw)
+	require.NoError(t, err, "failed creating postgres container")
+
+	// when
+	flywayContainer, err := flyway.RunContainer(ctx,
+		testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+		tcnetwork.WithNetwork([]string{"flyway"}, nw),
+		flyway.WithDatabaseUrl(postgresContainer.getNetworkUrl()),
+		flyway.WithUser(defaultPostgresDbUsername),
+		flyway.WithPassword(defaultPostgresDbPassword),
+		flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+		flyway.WithCommands(flyway.CommandClean, flyway.CommandMigrate, flyway.CommandValidate, flyway.CommandInfo),
+	)
+	require.NoError(t, err, "failed to run container")
+
+	// then
+	t.Cleanup(func() {
+		err := flywayContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate flyway container")
+
+		err = postgresContainer.Terminate(ctx)
+		require.NoError(t, err, "failed to terminate postgres container")
+	})
+
+	requireQuery(t, ctx, postgresContainer)
+
+	report, err := flywayContainer.Report(ctx)
+	require.NoError(t, err, "failed to get migrations report")
+	require.Len(t, report.Applied(), 3, "unexpected number of applied migrations")
 }
 
 func createTestPostgresContainer(ctx context.Context, nw *testcontainers.DockerNetwork) (*flywayPostgresTestContainer, error) {
@@ -95,6 +880,28 @@ func createTestPostgresContainer(ctx context.Context, nw *testcontainers.DockerN
 	}, nil
 }
 
+// listFlywayContainers returns the ids of the flyway containers created by the test session, running or not
+func listFlywayContainers(t testing.TB, ctx context.Context) []string {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	require.NoError(t, err, "failed creating docker client")
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", "org.testcontainers.sessionId="+testcontainers.SessionID()),
+			filters.Arg("ancestor", flyway.BuildFlywayImageVersion()),
+		),
+	})
+	require.NoError(t, err, "failed listing containers")
+
+	ids := make([]string, 0, len(containers))
+	for _, c := range containers {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
 func requireQuery(t testing.TB, ctx context.Context, postgresContainer *flywayPostgresTestContainer) {
 	postgresUrl, err := postgresContainer.getExternalUrl(ctx)
 	require.NoError(t, err, "failed getting external postgres url")
@@ -0,0 +1,5 @@
+CREATE TABLE things
+(
+    id       UUID NOT NULL PRIMARY KEY DEFAULT uuid_generate_v4(),
+    stuff_id UUID NOT NULL REFERENCES stuff (id)
+);
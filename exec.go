@@ -0,0 +1,208 @@
+package flyway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// ErrNoExecMode is returned when a flyway command is executed in a container which is not run in exec mode
+var ErrNoExecMode = errors.New("the container is not run in exec mode, please use flyway.WithExecMode()")
+
+// idleEntrypoint keeps the container running until it is terminated, in both the debian and alpine images
+var idleEntrypoint = []string{"tail", "-f", "/dev/null"}
+
+// WithExecMode keeps the container idle, the flyway commands are then executed on demand, e.g. with
+// FlywayContainer.Migrate, saving a container start per command. The settings a command needs, e.g. clean being
+// enabled, are only passed to it when it is executed, and WithTimeout limits the time each command may take. It
+// can't be combined with WithCommands, WithOutputType or the loggers, which only apply to the commands run by the
+// container.
+func WithExecMode() Option {
+	return func(o *options) error {
+		o.exec = true
+		return nil
+	}
+}
+
+// ExecMode reports whether the flyway commands are executed on demand, see WithExecMode
+func (c *FlywayContainer) ExecMode() bool {
+	return c.exec
+}
+
+// Migrate executes the migrate command, it requires the container to be run in exec mode
+func (c *FlywayContainer) Migrate(ctx context.Context) (*MigrateResult, error) {
+	output, err := c.execCommand(ctx, CommandMigrate)
+	if err != nil {
+		return nil, err
+	}
+	return output.Migrate, nil
+}
+
+// MigrateTo executes the migrate command up to and including a target version, it requires the container to be
+// run in exec mode
+func (c *FlywayContainer) MigrateTo(ctx context.Context, version string) (*MigrateResult, error) {
+	if version == "" {
+		return nil, errors.New("missing target version")
+	}
+
+	output, err := c.execCommand(ctx, CommandMigrate, fmt.Sprintf("-target=%s", version))
+	if err != nil {
+		return nil, err
+	}
+	return output.Migrate, nil
+}
+
+// MigrateStepwise applies the pending versioned migrations one version at a time, in order, and calls step once
+// each version is applied, e.g. to check the data survived. It stops at the first error, of flyway or of step. It
+// requires the container to be run in exec mode.
+func (c *FlywayContainer) MigrateStepwise(ctx context.Context, step func(ctx context.Context, version string) error) error {
+	info, err := c.Info(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := info.report()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range report.Pending() {
+		if migration.Version == "" {
+			continue // repeatable migrations are applied along with the versioned ones
+		}
+
+		if _, err := c.MigrateTo(ctx, migration.Version); err != nil {
+			return fmt.Errorf("failed to migrate to version %s: %w", migration.Version, err)
+		}
+		if err := step(ctx, migration.Version); err != nil {
+			return fmt.Errorf("step after version %s failed: %w", migration.Version, err)
+		}
+	}
+	return nil
+}
+
+// Info executes the info command, it requires the container to be run in exec mode
+func (c *FlywayContainer) Info(ctx context.Context) (*InfoResult, error) {
+	output, err := c.execCommand(ctx, CommandInfo)
+	if err != nil {
+		return nil, err
+	}
+	if output.Info == nil {
+		return nil, ErrNoReport
+	}
+	return output.Info, nil
+}
+
+// Validate executes the validate command, it requires the container to be run in exec mode
+func (c *FlywayContainer) Validate(ctx context.Context) (*ValidateResult, error) {
+	output, err := c.execCommand(ctx, CommandValidate)
+	if err != nil {
+		return nil, err
+	}
+	return output.Validate, nil
+}
+
+// Clean executes the clean command, it requires the container to be run in exec mode
+func (c *FlywayContainer) Clean(ctx context.Context) (*CleanResult, error) {
+	output, err := c.execCommand(ctx, CommandClean)
+	if err != nil {
+		return nil, err
+	}
+	return output.Clean, nil
+}
+
+// Repair executes the repair command, it requires the container to be run in exec mode
+func (c *FlywayContainer) Repair(ctx context.Context) (*RepairResult, error) {
+	output, err := c.execCommand(ctx, CommandRepair)
+	if err != nil {
+		return nil, err
+	}
+	return output.Repair, nil
+}
+
+// Baseline executes the baseline command, it requires the container to be run in exec mode
+func (c *FlywayContainer) Baseline(ctx context.Context) (*BaselineResult, error) {
+	output, err := c.execCommand(ctx, CommandBaseline)
+	if err != nil {
+		return nil, err
+	}
+	return output.Baseline, nil
+}
+
+// execCommand executes a flyway command with the json output type, a failure is returned as a MigrationError
+func (c *FlywayContainer) execCommand(ctx context.Context, cmd Command, args ...string) (*Output, error) {
+	if !c.exec {
+		return nil, ErrNoExecMode
+	}
+
+	cmdLine := append([]string{"flyway", fmt.Sprintf("%s=%s", outputTypeFlag, OutputTypeJSON)}, c.args...)
+	cmdLine = append(cmdLine, c.execArgs[cmd]...)
+	cmdLine = append(cmdLine, args...)
+	cmdLine = append(cmdLine, string(cmd))
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	exitCode, reader, err := c.Exec(ctx, cmdLine, tcexec.Multiplexed())
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("flyway %s did not complete within %s: %w", cmd, c.timeout, ctx.Err())
+		}
+		return nil, fmt.Errorf("failed to execute flyway %s: %w", cmd, err)
+	}
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flyway %s output: %w", cmd, err)
+	}
+
+	if exitCode != 0 {
+		migrationErr := &MigrationError{ExitCode: exitCode, Logs: string(content)}
+		migrationErr.parse()
+		return nil, migrationErr
+	}
+
+	output, err := parseOutput(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCommandsOutput([]Command{cmd}, output); err != nil {
+		return nil, fmt.Errorf("flyway %s failed: %w", cmd, err)
+	}
+	return output, nil
+}
+
+// commandArgs returns the arguments each command needs when it is executed, for the settings it needs which are not
+// set explicitly in the container environment
+func commandArgs(env map[string]string) map[Command][]string {
+	args := map[Command][]string{}
+	for cmd, command := range commands {
+		for key, value := range command.env {
+			if _, ok := env[key]; !ok {
+				args[cmd] = append(args[cmd], fmt.Sprintf("%s=%s", settingFlag(key), value))
+			}
+		}
+		slices.Sort(args[cmd])
+	}
+	return args
+}
+
+// settingFlag returns the command line flag of a flyway setting, e.g. -cleanDisabled for FLYWAY_CLEAN_DISABLED
+func settingFlag(key string) string {
+	words := strings.Split(strings.ToLower(strings.TrimPrefix(key, flywayEnvPrefix)), "_")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return "-" + strings.Join(words, "")
+}
@@ -0,0 +1,90 @@
+package flyway
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// hangingContainer is a container whose commands never complete, until their context is done
+type hangingContainer struct {
+	testcontainers.Container
+}
+
+func (c *hangingContainer) Exec(ctx context.Context, _ []string, _ ...tcexec.ProcessOption) (int, io.Reader, error) {
+	<-ctx.Done()
+	return 0, nil, ctx.Err()
+}
+
+func TestFlyway_execCommandsNoExecMode(t *testing.T) {
+	container := &FlywayContainer{}
+
+	_, err := container.Migrate(context.Background())
+	require.ErrorIs(t, err, ErrNoExecMode)
+
+	_, err = container.Clean(context.Background())
+	require.ErrorIs(t, err, ErrNoExecMode)
+}
+
+func TestFlyway_commandArgs(t *testing.T) {
+	args := commandArgs(map[string]string{})
+	require.Equal(t, []string{"-cleanDisabled=false"}, args[CommandClean], "expected clean to be enabled when executed")
+	require.Empty(t, args[CommandMigrate])
+
+	args = commandArgs(map[string]string{flywayEnvCleanDisabledKey: "true"})
+	require.Empty(t, args[CommandClean], "expected the explicit setting to be kept")
+}
+
+func TestFlyway_settingFlag(t *testing.T) {
+	require.Equal(t, "-cleanDisabled", settingFlag(flywayEnvCleanDisabledKey))
+	require.Equal(t, "-target", settingFlag(flywayEnvTargetKey))
+	require.Equal(t, "-baselineOnMigrate", settingFlag("FLYWAY_BASELINE_ON_MIGRATE"))
+}
+
+func TestFlyway_migrateStepwiseNoExecMode(t *testing.T) {
+	container := &FlywayContainer{}
+
+	_, err := container.MigrateTo(context.Background(), "")
+	require.EqualError(t, err, "missing target version")
+
+	err = container.MigrateStepwise(context.Background(), func(context.Context, string) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrNoExecMode)
+}
+
+func TestFlyway_execCommandTimeout(t *testing.T) {
+	container := &FlywayContainer{Container: &hangingContainer{}, exec: true, timeout: 10 * time.Millisecond}
+
+	_, err := container.Migrate(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "flyway migrate did not complete within 10ms")
+}
+
+func TestFlyway_execModeInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opt      Option
+		expected string
+	}{
+		{name: "commands", opt: WithCommands(CommandClean, CommandMigrate), expected: "flyway.WithCommands()"},
+		{name: "output type", opt: WithOutputType(OutputTypeJSON), expected: "flyway.WithOutputType()"},
+		{name: "logger", opt: WithLogger(t), expected: "flyway.WithLogger()"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			container, err := RunContainer(context.Background(), WithExecMode(), testCase.opt)
+			require.ErrorContains(tt, err, testCase.expected+" can't be combined with flyway.WithExecMode()")
+			require.Nil(tt, container)
+		})
+	}
+}
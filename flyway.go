@@ -2,8 +2,9 @@ package flyway
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/testcontainers/testcontainers-go"
@@ -16,49 +17,115 @@ const (
 
 	defaultImagePattern = "flyway/flyway:%s"
 	defaultTable        = "schema_version"
-	migrateCmd          = "migrate"
-	infoCmd             = "info"
 
 	// wait strategies
 	defaultTimeout time.Duration = 30 * time.Second
 
 	// flyway environment variables
-	flywayEnvUserKey           = "FLYWAY_USER"
-	flywayEnvPasswordKey       = "FLYWAY_PASSWORD"
-	flywayEnvUrlKey            = "FLYWAY_URL"
-	flywayEnvGroupKey          = "FLYWAY_GROUP"
-	flywayEnvTableKey          = "FLYWAY_TABLE"
-	flywayEnvConnectRetriesKey = "FLYWAY_CONNECT_RETRIES"
-	flywayEnvLocationsKey      = "FLYWAY_LOCATIONS"
-)
-
-var (
-	waitForValidated = wait.ForLog(`Successfully validated \d+ migration[s]?`).AsRegexp().WithOccurrence(1)
-	waitForApplied   = wait.ForLog(`Successfully applied \d+ migration[s]? to schema`).AsRegexp().WithOccurrence(1)
+	flywayEnvUserKey            = "FLYWAY_USER"
+	flywayEnvPasswordKey        = "FLYWAY_PASSWORD"
+	flywayEnvUrlKey             = "FLYWAY_URL"
+	flywayEnvGroupKey           = "FLYWAY_GROUP"
+	flywayEnvTableKey           = "FLYWAY_TABLE"
+	flywayEnvConnectRetriesKey  = "FLYWAY_CONNECT_RETRIES"
+	flywayEnvLocationsKey       = "FLYWAY_LOCATIONS"
+	flywayEnvCleanDisabledKey   = "FLYWAY_CLEAN_DISABLED"
+	flywayEnvSchemasKey         = "FLYWAY_SCHEMAS"
+	flywayEnvDefaultSchemaKey   = "FLYWAY_DEFAULT_SCHEMA"
+	flywayEnvCreateSchemasKey   = "FLYWAY_CREATE_SCHEMAS"
+	flywayEnvTargetKey          = "FLYWAY_TARGET"
+	flywayEnvBaselineVersionKey = "FLYWAY_BASELINE_VERSION"
+	flywayEnvLicenseKeyKey      = "FLYWAY_LICENSE_KEY"
 )
 
 // FlywayContainer represents the Flyway container type used in the module
 type FlywayContainer struct {
 	testcontainers.Container
+	outputType OutputType
+	commands   []Command
+	// args are the extra command line arguments passed to flyway
+	args []string
+	// exec is set when the commands are executed on demand, see WithExecMode
+	exec bool
+	// execArgs are the arguments each command needs when it is executed on demand
+	execArgs map[Command][]string
+	// timeout is the time each command executed on demand may take
+	timeout time.Duration
+	// network is the network the database target was attached to by the module, nil if none
+	network *targetNetwork
+}
+
+// Terminate terminates the container, then detaches the database target from the network the module attached it
+// to, if any
+func (c *FlywayContainer) Terminate(ctx context.Context) error {
+	return errors.Join(c.Container.Terminate(ctx), c.network.detach(ctx))
+}
+
+// Option is an option for the Flyway module, it configures the module itself rather than the container request
+type Option func(*options) error
+
+// Customize is a NOOP. It's defined to satisfy the testcontainers.ContainerCustomizer interface.
+func (o Option) Customize(*testcontainers.GenericContainerRequest) error {
+	// NOOP to satisfy interface.
+	return nil
+}
+
+type options struct {
+	timeout    time.Duration
+	outputType OutputType
+	commands   []Command
+	// args are the extra command line arguments passed to flyway, see WithCommandArgs
+	args       []string
+	target     DatabaseTarget
+	hostAccess HostAccess
+	// uncheckedSettings are the flyway settings which are not validated, see WithUncheckedSetting
+	uncheckedSettings map[string]string
+	// customDrivers are the subprotocols of the jdbc drivers added to the image, see WithCustomDriver
+	customDrivers []string
+	// logHandlers receive the lines logged by flyway while it runs
+	logHandlers []func(LogEntry)
+	// exec keeps the container idle, the commands being executed on demand
+	exec bool
+	// notExecOptions are the options applied which have no effect in exec mode
+	notExecOptions []string
+}
+
+func defaultOptions() options {
+	return options{
+		timeout:    defaultTimeout,
+		outputType: OutputTypeText,
+		commands:   []Command{CommandMigrate, CommandInfo},
+	}
+}
+
+func (o options) cmd() []string {
+	var cmd []string
+	if o.outputType != OutputTypeText {
+		cmd = append(cmd, fmt.Sprintf("%s=%s", outputTypeFlag, o.outputType))
+	}
+	cmd = append(cmd, o.args...)
+	for _, command := range o.commands {
+		cmd = append(cmd, string(command))
+	}
+	return cmd
+}
+
+func (o options) waitStrategy() wait.Strategy {
+	strategy := &exitStrategy{
+		timeout:      o.timeout,
+		pollInterval: defaultPollInterval,
+	}
+	if o.outputType == OutputTypeText {
+		// with json the logs are replaced by the output, success is checked by decoding it
+		strategy.commands = o.commands
+	}
+	return strategy
 }
 
 // RunContainer creates an instance of the Flyway container type
 func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*FlywayContainer, error) {
 	req := testcontainers.ContainerRequest{
-		Env: map[string]string{
-			flywayEnvGroupKey:          "true",
-			flywayEnvTableKey:          defaultTable,
-			flywayEnvConnectRetriesKey: "3",
-			flywayEnvLocationsKey:      fmt.Sprintf("filesystem:%s", DefaultMigrationsPath),
-		},
-		Cmd: []string{
-			migrateCmd, infoCmd,
-		},
-		WaitingFor: wait.ForAll(
-			wait.ForExit().WithExitTimeout(defaultTimeout),
-			waitForApplied,
-			waitForValidated,
-		),
+		Env: map[string]string{},
 	}
 
 	genericContainerReq := testcontainers.GenericContainerRequest{
@@ -66,39 +133,163 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 		Started:          true,
 	}
 
+	settings := defaultOptions()
 	for _, opt := range opts {
+		if apply, ok := opt.(Option); ok {
+			if err := apply(&settings); err != nil {
+				return nil, fmt.Errorf("failed to apply flyway option: %w", err)
+			}
+		}
 		if err := opt.Customize(&genericContainerReq); err != nil {
 			return nil, fmt.Errorf("failed to customize flyway container: %w", err)
 		}
 	}
 
-	if err := parseRequest(genericContainerReq); err != nil {
+	if settings.exec && len(settings.notExecOptions) > 0 {
+		return nil, fmt.Errorf("%s can't be combined with flyway.WithExecMode(), the commands are executed on demand", strings.Join(settings.notExecOptions, ", "))
+	}
+
+	for key, value := range settings.uncheckedSettings {
+		genericContainerReq.Env[key] = value
+	}
+
+	if settings.hostAccess != "" {
+		if settings.target != nil {
+			return nil, errors.New("host access can't be combined with a database target")
+		}
+		if err := withHostAccess(&genericContainerReq, settings.hostAccess); err != nil {
+			return nil, err
+		}
+	}
+
+	var attached *targetNetwork
+	if settings.target != nil {
+		var err error
+		if attached, err = withConnection(ctx, &genericContainerReq, settings.target); err != nil {
+			return nil, err
+		}
+	}
+
+	// defaults, applied last so the settings needed by the database target take precedence over them
+	for key, value := range map[string]string{
+		flywayEnvGroupKey:          "true",
+		flywayEnvTableKey:          defaultTable,
+		flywayEnvConnectRetriesKey: "3",
+	} {
+		if _, ok := genericContainerReq.Env[key]; !ok {
+			genericContainerReq.Env[key] = value
+		}
+	}
+
+	// defaults which depend on the module options, unless overridden by the request options
+	if settings.exec {
+		if len(genericContainerReq.Entrypoint) == 0 {
+			genericContainerReq.Entrypoint = idleEntrypoint
+		}
+	} else {
+		if len(genericContainerReq.Cmd) == 0 {
+			genericContainerReq.Cmd = settings.cmd()
+		}
+		if genericContainerReq.WaitingFor == nil {
+			genericContainerReq.WaitingFor = settings.waitStrategy()
+		}
+	}
+	var execArgs map[Command][]string
+	if settings.exec {
+		// the settings needed by a command are only passed to it when it is executed
+		execArgs = commandArgs(genericContainerReq.Env)
+	} else {
+		for _, command := range settings.commands {
+			for key, value := range commands[command].env {
+				if _, ok := genericContainerReq.Env[key]; !ok {
+					genericContainerReq.Env[key] = value
+				}
+			}
+		}
+	}
+
+	if len(settings.logHandlers) > 0 {
+		withLogHandlers(&genericContainerReq, settings.commands, settings.logHandlers)
+	}
+
+	flywayContainer, err := runContainer(ctx, genericContainerReq, settings)
+	if err != nil {
+		// the network the database target was attached to is not needed anymore
+		return nil, errors.Join(err, attached.detach(ctx))
+	}
+
+	flywayContainer.network = attached
+	flywayContainer.execArgs = execArgs
+	return flywayContainer, nil
+}
+
+// runContainer runs the flyway container of a complete request, and checks the commands succeeded
+func runContainer(ctx context.Context, genericContainerReq testcontainers.GenericContainerRequest, settings options) (*FlywayContainer, error) {
+	if err := parseRequest(genericContainerReq, settings); err != nil {
 		return nil, err
 	}
 
 	container, err := testcontainers.GenericContainer(ctx, genericContainerReq)
-	if err != nil {
+	if container == nil {
 		return nil, err
 	}
 
-	state, err := container.State(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get container state: %w", err)
-	} else if state.ExitCode != 0 {
-		if state.Health != nil {
-			return nil, fmt.Errorf("the container state is not healthy: %d/%s", state.ExitCode, state.Health.Status)
+	if err := checkContainer(ctx, container, err, settings); err != nil {
+		// the container is not returned, so nobody else could terminate it
+		if terminateErr := container.Terminate(ctx); terminateErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed to terminate flyway container: %w", terminateErr))
 		}
-		return nil, fmt.Errorf("the container state is not healthy: %d", state.ExitCode)
+		return nil, err
 	}
 
 	return &FlywayContainer{
-		Container: container,
+		Container:  container,
+		outputType: settings.outputType,
+		commands:   settings.commands,
+		args:       settings.args,
+		exec:       settings.exec,
+		timeout:    settings.timeout,
 	}, nil
 }
 
-func parseRequest(req testcontainers.GenericContainerRequest) error {
+// checkContainer checks the container ran the flyway commands successfully, given the error returned when it was
+// started
+func checkContainer(ctx context.Context, container testcontainers.Container, startErr error, settings options) error {
+	if startErr != nil {
+		var migrationErr *MigrationError
+		if errors.As(startErr, &migrationErr) {
+			return migrationErr
+		}
+
+		// a failed migration makes the other wait strategies time out, report the failure instead
+		if state, err := container.State(ctx); err == nil && !state.Running && state.ExitCode != 0 {
+			return newMigrationError(ctx, container, state.ExitCode)
+		}
+		return startErr
+	}
+
+	state, err := container.State(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get container state: %w", err)
+	} else if state.ExitCode != 0 {
+		return newMigrationError(ctx, container, state.ExitCode)
+	}
+
+	if settings.outputType == OutputTypeJSON && !settings.exec {
+		output, err := decodeOutput(ctx, container)
+		if err != nil {
+			return err
+		}
+		if err := checkCommandsOutput(settings.commands, output); err != nil {
+			return fmt.Errorf("flyway commands failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func parseRequest(req testcontainers.GenericContainerRequest, settings options) error {
 	// parse migrations
-	const migrationsErrMessage string = "Please use flyway.WithMigrations() option to provide migrations"
+	const migrationsErrMessage string = "Please use flyway.WithMigrations(), flyway.WithMigrationsFS() or flyway.WithInlineMigrations() option to provide migrations"
 
 	if req.Env[flywayEnvLocationsKey] == "" {
 		return fmt.Errorf("missing migrations: environment variable %s is empty. %s", flywayEnvLocationsKey, migrationsErrMessage)
@@ -106,16 +297,26 @@ func parseRequest(req testcontainers.GenericContainerRequest) error {
 
 	if len(req.Files) == 0 {
 		return fmt.Errorf("missing migrations: no files provided. %s", migrationsErrMessage)
-	} else {
+	}
+	if err := checkDuplicateFiles(req.Files); err != nil {
+		return err
+	}
+
+	for _, location := range splitLocations(req.Env[flywayEnvLocationsKey]) {
+		containerPath, ok := strings.CutPrefix(location, filesystemLocationPrefix)
+		if !ok {
+			continue // other location types, e.g. classpath, are not provided as container files
+		}
+
 		migrationsFound := false
 		for _, file := range req.Files {
-			if file.ContainerFilePath == DefaultMigrationsPath {
+			if file.ContainerFilePath == containerPath || strings.HasPrefix(file.ContainerFilePath, strings.TrimSuffix(containerPath, "/")+"/") {
 				migrationsFound = true
 			}
 		}
 
 		if !migrationsFound {
-			return fmt.Errorf("missing migrations: %s", migrationsErrMessage)
+			return fmt.Errorf("missing migrations: no files provided for location %s. %s", location, migrationsErrMessage)
 		}
 	}
 
@@ -123,67 +324,93 @@ func parseRequest(req testcontainers.GenericContainerRequest) error {
 	if req.Env[flywayEnvUrlKey] == "" {
 		return fmt.Errorf("missing database url: environment variable %s is empty", flywayEnvUrlKey)
 	}
+	if _, err := parseJDBCURL(req.Env[flywayEnvUrlKey], settings.customDrivers); err != nil {
+		return fmt.Errorf("invalid database url: environment variable %s: %w", flywayEnvUrlKey, err)
+	}
 	if req.Env[flywayEnvUserKey] == "" {
 		return fmt.Errorf("missing user: environment variable %s is empty", flywayEnvUserKey)
 	}
-	if req.Env[flywayEnvPasswordKey] == "" {
-		return fmt.Errorf("missing password: environment variable %s is empty", flywayEnvPasswordKey)
+	// an empty password is allowed, e.g. by databases trusting their clients
+	if _, ok := req.Env[flywayEnvPasswordKey]; !ok {
+		return fmt.Errorf("missing password: environment variable %s is not set", flywayEnvPasswordKey)
 	}
 
-	return nil
+	// parse all the other settings
+	return parseSettings(req.Env, settings.uncheckedSettings)
 }
 
 func WithUser(user string) testcontainers.CustomizeRequestOption {
-	return withEnvSetting("FLYWAY_USER", user)
+	return withSetting(flywayEnvUserKey, user)
 }
 
 func WithPassword(password string) testcontainers.CustomizeRequestOption {
-	return withEnvSetting("FLYWAY_PASSWORD", password)
+	return withSetting(flywayEnvPasswordKey, password)
 }
 
 func WithDatabaseUrl(dbUrl string) testcontainers.CustomizeRequestOption {
-	return withEnvSetting("FLYWAY_URL", dbUrl)
+	return withSetting(flywayEnvUrlKey, dbUrl)
 }
 
-func WithTimeout(timeout time.Duration) testcontainers.CustomizeRequestOption {
-	return func(req *testcontainers.GenericContainerRequest) error {
-		req.WaitingFor = wait.ForAll(
-			wait.ForExit().WithExitTimeout(timeout),
-			waitForApplied,
-			waitForValidated,
-		)
-
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) error {
+		o.timeout = timeout
 		return nil
 	}
 }
 
-func WithGroup(group string) testcontainers.CustomizeRequestOption {
-	return withEnvSetting("GROUP", group)
+// WithGroup sets whether all the pending migrations are applied within a single transaction, enabled by default
+func WithGroup(group bool) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvGroupKey, group)
 }
 
 func WithTable(table string) testcontainers.CustomizeRequestOption {
-	return withEnvSetting("FLYWAY_TABLE", table)
+	return withSetting(flywayEnvTableKey, table)
 }
 
-func WithConnectRetries(retries int) testcontainers.CustomizeRequestOption {
-	return withEnvSetting("FLYWAY_CONNECT_RETRIES", strconv.Itoa(retries))
+// WithSchemas sets the schemas managed by flyway, the first one holds the schema history table unless a
+// default schema is set
+func WithSchemas(schemas ...string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if len(schemas) == 0 {
+			return errors.New("at least one schema must be provided")
+		}
+		for _, schema := range schemas {
+			if strings.TrimSpace(schema) == "" || strings.Contains(schema, ",") {
+				return fmt.Errorf("invalid schema name: %q", schema)
+			}
+		}
+
+		return withSetting(flywayEnvSchemasKey, schemas)(req)
+	}
 }
 
-func withEnvSetting(key, group string) testcontainers.CustomizeRequestOption {
-	return testcontainers.WithEnv(map[string]string{
-		key: group,
-	})
+// WithDefaultSchema sets the default schema, which holds the schema history table
+func WithDefaultSchema(schema string) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvDefaultSchemaKey, schema)
 }
 
-func WithMigrations(absHostFilePath string) testcontainers.CustomizeRequestOption {
-	return func(req *testcontainers.GenericContainerRequest) error {
-		req.Files = []testcontainers.ContainerFile{{
-			HostFilePath:      absHostFilePath,
-			ContainerFilePath: DefaultMigrationsPath,
-		}}
+// WithCreateSchemas sets whether flyway creates the schemas it manages when they do not exist, enabled by default
+func WithCreateSchemas(create bool) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvCreateSchemasKey, create)
+}
 
-		return withEnvSetting("FLYWAY_LOCATIONS", fmt.Sprintf("filesystem:%s", DefaultMigrationsPath))(req)
-	}
+// WithTarget sets the version up to which the migrations are applied, e.g. 1.1, rather than the latest one
+func WithTarget(version string) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvTargetKey, version)
+}
+
+// WithBaselineVersion sets the version the baseline command tags an existing database with, 1 by default
+func WithBaselineVersion(version string) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvBaselineVersionKey, version)
+}
+
+// WithLicenseKey sets the license key of the flyway edition, required by commands such as check and undo
+func WithLicenseKey(key string) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvLicenseKeyKey, key)
+}
+
+func WithConnectRetries(retries int) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvConnectRetriesKey, retries)
 }
 
 func BuildFlywayImageVersion(version ...string) string {
@@ -18,11 +18,13 @@ const (
 
 func TestFlyway_parseInvalidRequest(t *testing.T) {
 	tests := []struct {
-		name string
-		opts []testcontainers.ContainerCustomizer
+		name     string
+		opts     []testcontainers.ContainerCustomizer
+		expected string
 	}{
 		{
-			name: "missing database url",
+			name:     "missing database url",
+			expected: "missing database url: environment variable FLYWAY_URL is empty",
 			opts: []testcontainers.ContainerCustomizer{
 				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
 				flyway.WithUser(defaultPostgresDbUsername),
@@ -31,7 +33,19 @@ func TestFlyway_parseInvalidRequest(t *testing.T) {
 			},
 		},
 		{
-			name: "missing user",
+			name:     "invalid database url",
+			expected: `invalid database url: environment variable FLYWAY_URL: unsupported subprotocol "postgres"`,
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgres://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+			},
+		},
+		{
+			name:     "missing user",
+			expected: "missing user: environment variable FLYWAY_USER is empty",
 			opts: []testcontainers.ContainerCustomizer{
 				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
 				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
@@ -40,21 +54,175 @@ func TestFlyway_parseInvalidRequest(t *testing.T) {
 			},
 		},
 		{
-			name: "missing password",
+			name:     "missing password",
+			expected: "missing password: environment variable FLYWAY_PASSWORD is not set",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+			},
+		},
+		{
+			name:     "missing migrations",
+			expected: "missing migrations: environment variable FLYWAY_LOCATIONS is empty",
 			opts: []testcontainers.ContainerCustomizer{
 				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
 				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
 				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+			},
+		},
+		{
+			name:     "missing migrations for location",
+			expected: "missing migrations: no files provided for location filesystem:/flyway/other",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
 				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				testcontainers.WithEnv(map[string]string{"FLYWAY_LOCATIONS": "filesystem:/flyway/sql,filesystem:/flyway/other"}),
 			},
 		},
 		{
-			name: "missing migrations",
+			name:     "duplicate migrations",
+			expected: "duplicate container file: /flyway/sql/V1.1__create_table_stuff.sql is provided more than once",
 			opts: []testcontainers.ContainerCustomizer{
 				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
 				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
 				flyway.WithUser(defaultPostgresDbUsername),
 				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+			},
+		},
+		{
+			name:     "duplicate container files",
+			expected: "duplicate container file: /flyway/sql/V9__custom.sql is provided more than once",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				// set directly rather than with the module options, which check the files they add
+				testcontainers.CustomizeRequestOption(func(req *testcontainers.GenericContainerRequest) error {
+					file := testcontainers.ContainerFile{
+						HostFilePath:      filepath.Join("testdata", flyway.DefaultMigrationsPath, "V1.1__create_table_stuff.sql"),
+						ContainerFilePath: "/flyway/sql/V9__custom.sql",
+					}
+					req.Files = append(req.Files, file, file)
+					return nil
+				}),
+			},
+		},
+		{
+			name:     "invalid placeholder",
+			expected: `invalid placeholder "app.user"`,
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithPlaceholders(map[string]string{"app.user": "app"}),
+			},
+		},
+		{
+			name:     "missing schemas",
+			expected: "at least one schema must be provided",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithSchemas(),
+			},
+		},
+		{
+			name:     "invalid schema",
+			expected: `invalid schema name: "stuff,things"`,
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithSchemas("public", "stuff,things"),
+			},
+		},
+		{
+			name:     "mistyped setting",
+			expected: "invalid setting: environment variable FLYWAY_GROUP expects a boolean",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				testcontainers.WithEnv(map[string]string{"FLYWAY_GROUP": "my_group"}),
+			},
+		},
+		{
+			name:     "unknown setting",
+			expected: "unknown setting: environment variable FLYWAY_GRUOP is not supported",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				testcontainers.WithEnv(map[string]string{"FLYWAY_GRUOP": "true"}),
+			},
+		},
+		{
+			name:     "setting without the flyway prefix",
+			expected: `invalid setting "BASELINE_ON_MIGRATE": flyway environment variables start with FLYWAY_`,
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithSetting("BASELINE_ON_MIGRATE", "true"),
+			},
+		},
+		{
+			name:     "empty target",
+			expected: "invalid setting: environment variable FLYWAY_TARGET expects a non-empty string",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithTarget(""),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+			},
+		},
+		{
+			name:     "missing commands",
+			expected: "at least one command must be provided",
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithCommands(),
+			},
+		},
+		{
+			name:     "unsupported command",
+			expected: `unsupported command: "upgrade"`,
+			opts: []testcontainers.ContainerCustomizer{
+				testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+				flyway.WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db?sslmode=disable"),
+				flyway.WithUser(defaultPostgresDbUsername),
+				flyway.WithPassword(defaultPostgresDbPassword),
+				flyway.WithMigrations(filepath.Join("testdata", flyway.DefaultMigrationsPath)),
+				flyway.WithCommands(flyway.CommandMigrate, "upgrade"),
 			},
 		},
 	}
@@ -67,7 +235,7 @@ func TestFlyway_parseInvalidRequest(t *testing.T) {
 				testCase.opts...,
 			)
 
-			require.Error(tt, err, "expected error")
+			require.ErrorContains(tt, err, testCase.expected)
 			require.Nil(tt, flywayContainer, "expected nil container")
 		})
 	}
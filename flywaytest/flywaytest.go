@@ -0,0 +1,112 @@
+// Package flywaytest provides test helpers around the flyway container, so the flyway package does not depend on
+// the testing package
+package flywaytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/CyberOwlTeam/flyway"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// MustMigrate runs a flyway container with the given options and fails the test, with the flyway error and logs,
+// if the migrations are not applied. The container is terminated at the end of the test. It does not support the
+// exec mode, where nothing is migrated when the container runs, please use MustRun instead.
+func MustMigrate(t testing.TB, opts ...testcontainers.ContainerCustomizer) *flyway.FlywayContainer {
+	t.Helper()
+
+	container := MustRun(t, opts...)
+	if container.ExecMode() {
+		t.Fatalf("flyway.WithExecMode() can't be used with MustMigrate, please use MustRun")
+	}
+	return container
+}
+
+// MustRun runs a flyway container with the given options, e.g. an idle container in exec mode, and fails the test,
+// with the flyway error and logs, if it can't run. The container is terminated at the end of the test.
+func MustRun(t testing.TB, opts ...testcontainers.ContainerCustomizer) *flyway.FlywayContainer {
+	t.Helper()
+
+	container, err := flyway.RunContainer(context.Background(), opts...)
+	if err != nil {
+		fatal(t, "failed to run flyway container", err)
+	}
+
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Errorf("failed to terminate flyway container: %s", err)
+		}
+	})
+	return container
+}
+
+// UpgradeHooks are called by UpgradeCompatibility for each version of the migrations
+type UpgradeHooks struct {
+	// AtVersion is called once the database is migrated up to the version, e.g. to insert fixtures. Optional.
+	AtVersion func(t *testing.T, version string)
+	// AtHead is called once the database is then migrated to the latest version, e.g. to check the fixtures
+	// survived. Optional.
+	AtHead func(t *testing.T, version string)
+}
+
+// UpgradeCompatibility proves every historical state of the database can reach the latest schema. For each version
+// of the migrations found in the directory, in a sub test named after it, the database is cleaned, migrated up to
+// the version, then to the latest version, the hooks being called in between. The options provide the database,
+// e.g. with flyway.WithPostgres(), but not the migrations.
+func UpgradeCompatibility(t *testing.T, migrationsDir string, hooks UpgradeHooks, opts ...testcontainers.ContainerCustomizer) {
+	t.Helper()
+	ctx := context.Background()
+
+	// the caller's options are copied, appending to them could overwrite their backing array
+	execOpts := make([]testcontainers.ContainerCustomizer, 0, len(opts)+2)
+	execOpts = append(execOpts, opts...)
+	execOpts = append(execOpts, flyway.WithExecMode(), flyway.WithMigrations(migrationsDir))
+	container := MustRun(t, execOpts...)
+
+	report, err := container.Report(ctx)
+	if err != nil {
+		fatal(t, "failed to get the flyway migrations", err)
+	}
+
+	for _, migration := range report.Migrations {
+		if migration.Version == "" {
+			continue // repeatable migrations are applied along with the versioned ones
+		}
+
+		version := migration.Version
+		t.Run(version, func(t *testing.T) {
+			if _, err := container.Clean(ctx); err != nil {
+				fatal(t, "failed to clean the database", err)
+			}
+
+			if _, err := container.MigrateTo(ctx, version); err != nil {
+				fatal(t, fmt.Sprintf("failed to migrate to version %s", version), err)
+			}
+			if hooks.AtVersion != nil {
+				hooks.AtVersion(t, version)
+			}
+
+			if _, err := container.Migrate(ctx); err != nil {
+				fatal(t, fmt.Sprintf("failed to migrate from version %s to the latest version", version), err)
+			}
+			if hooks.AtHead != nil {
+				hooks.AtHead(t, version)
+			}
+		})
+	}
+}
+
+// fatal fails the test with the error, and the flyway logs when they are known
+func fatal(t testing.TB, message string, err error) {
+	t.Helper()
+
+	var migrationErr *flyway.MigrationError
+	if errors.As(err, &migrationErr) && migrationErr.Logs != "" {
+		t.Fatalf("%s: %s\n\nflyway logs:\n%s", message, err, migrationErr.Logs)
+	}
+	t.Fatalf("%s: %s", message, err)
+}
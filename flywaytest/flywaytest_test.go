@@ -0,0 +1,88 @@
+package flywaytest
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/CyberOwlTeam/flyway"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// fatalTB records the failure of a test, and stops it like testing.TB does. The other methods are the ones of the
+// running test.
+type fatalTB struct {
+	testing.TB
+	failure  string
+	cleanups int
+}
+
+func (tb *fatalTB) Helper() {}
+
+func (tb *fatalTB) Cleanup(func()) {
+	tb.cleanups++
+}
+
+func (tb *fatalTB) Fatalf(format string, args ...any) {
+	tb.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// runFatal runs f in its own goroutine, as the test stops it on failure
+func runFatal(f func()) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f()
+	}()
+	wg.Wait()
+}
+
+func TestFlyway_mustMigrateInvalidRequest(t *testing.T) {
+	tb := &fatalTB{TB: t}
+
+	runFatal(func() {
+		MustMigrate(tb,
+			testcontainers.WithImage(flyway.BuildFlywayImageVersion()),
+			flyway.WithDatabaseUrl("jdbc:postgres://localhost:5432/test_db"),
+			flyway.WithUser("test-user"),
+			flyway.WithPassword("test-password"),
+			flyway.WithInlineMigrations(flyway.Migration{Version: "1", Description: "create stuff", SQL: "CREATE TABLE stuff (name TEXT);"}),
+		)
+	})
+
+	require.Contains(t, tb.failure, `failed to run flyway container: invalid database url`)
+	require.Zero(t, tb.cleanups, "expected no container to terminate")
+}
+
+func TestFlyway_fatalMigrationError(t *testing.T) {
+	tb := &fatalTB{TB: t}
+	err := fmt.Errorf("failed to start container: %w", &flyway.MigrationError{
+		ExitCode: 1,
+		Script:   "V2__alter_table_stuff.sql",
+		Message:  `relation "stuff" does not exist`,
+		Logs:     "ERROR: Migration V2__alter_table_stuff.sql failed\n",
+	})
+
+	runFatal(func() {
+		fatal(tb, "failed to run flyway container", err)
+	})
+
+	require.Contains(t, tb.failure, `failed to run flyway container: failed to start container: flyway exited with code 1: migration V2__alter_table_stuff.sql failed: relation "stuff" does not exist`)
+	require.Contains(t, tb.failure, "flyway logs:\nERROR: Migration V2__alter_table_stuff.sql failed")
+}
+
+func TestFlyway_fatalError(t *testing.T) {
+	tb := &fatalTB{TB: t}
+
+	runFatal(func() {
+		fatal(tb, "failed to clean the database", errors.New("connection refused"))
+	})
+
+	require.Equal(t, "failed to clean the database: connection refused", tb.failure)
+}
@@ -0,0 +1,79 @@
+package flyway
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// HostAccess is how the flyway container reaches a database running on the host, rather than in a container
+type HostAccess string
+
+const (
+	// HostAccessGateway maps host.docker.internal to the host gateway, the local hosts of the database url are
+	// replaced by it. On Linux the gateway is the docker bridge, so the database must listen on it, e.g. on 0.0.0.0
+	// rather than 127.0.0.1, otherwise please use HostAccessNetwork.
+	HostAccessGateway HostAccess = "gateway"
+	// HostAccessNetwork runs the flyway container on the host network, the database url is unchanged. It is only
+	// supported on Linux.
+	HostAccessNetwork HostAccess = "network"
+
+	hostGatewayName = "host.docker.internal"
+)
+
+// localHostRegexp matches the local host of a jdbc url, e.g. jdbc:postgresql://localhost:5432/db, including the
+// oracle thin flavours jdbc:oracle:thin:@//localhost:1521/service and jdbc:oracle:thin:@localhost:1521:sid
+var localHostRegexp = regexp.MustCompile(`^(jdbc:oracle:thin:[^@]*@(?://)?|jdbc:[^/]*//(?:[^@/]*@)?)(localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1\])([:/;?]|$)`)
+
+// WithHostAccess lets the flyway container reach a database running on the host, e.g. started by another tool
+// than testcontainers. The database url can then point at localhost, see HostAccessGateway for the address the
+// database must listen on. It can't be combined with a database target.
+func WithHostAccess(access HostAccess) Option {
+	return func(o *options) error {
+		switch access {
+		case HostAccessGateway:
+		case HostAccessNetwork:
+			if runtime.GOOS != "linux" {
+				return fmt.Errorf("host access %q is only supported on linux, please use %q", access, HostAccessGateway)
+			}
+		default:
+			return fmt.Errorf("unsupported host access: %q", access)
+		}
+
+		o.hostAccess = access
+		return nil
+	}
+}
+
+// withHostAccess configures the container to reach the host, and rewrites the database url accordingly
+func withHostAccess(req *testcontainers.GenericContainerRequest, access HostAccess) error {
+	if access == HostAccessNetwork && len(req.Networks) > 0 {
+		return errors.New("host network access can't be combined with other networks")
+	}
+
+	modifier := req.HostConfigModifier
+	req.HostConfigModifier = func(hostConfig *container.HostConfig) {
+		if modifier != nil {
+			modifier(hostConfig)
+		}
+
+		switch access {
+		case HostAccessGateway:
+			hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, hostGatewayName+":host-gateway")
+		case HostAccessNetwork:
+			hostConfig.NetworkMode = "host"
+		}
+	}
+
+	if access == HostAccessGateway {
+		if url, ok := req.Env[flywayEnvUrlKey]; ok {
+			req.Env[flywayEnvUrlKey] = localHostRegexp.ReplaceAllString(url, "${1}"+hostGatewayName+"${3}")
+		}
+	}
+	return nil
+}
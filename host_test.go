@@ -0,0 +1,61 @@
+package flyway
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestFlyway_withHostAccessGateway(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "jdbc:postgresql://localhost:5432/test_db", expected: "jdbc:postgresql://host.docker.internal:5432/test_db"},
+		{url: "jdbc:mysql://user@127.0.0.1/test_db", expected: "jdbc:mysql://user@host.docker.internal/test_db"},
+		{url: "jdbc:sqlserver://localhost;databaseName=master", expected: "jdbc:sqlserver://host.docker.internal;databaseName=master"},
+		{url: "jdbc:oracle:thin:@//[::1]:1521/FREEPDB1", expected: "jdbc:oracle:thin:@//host.docker.internal:1521/FREEPDB1"},
+		{url: "jdbc:oracle:thin:@localhost:1521:FREE", expected: "jdbc:oracle:thin:@host.docker.internal:1521:FREE"},
+		{url: "jdbc:oracle:thin:system/secret@localhost:1521/FREEPDB1", expected: "jdbc:oracle:thin:system/secret@host.docker.internal:1521/FREEPDB1"},
+		{url: "jdbc:postgresql://localhost", expected: "jdbc:postgresql://host.docker.internal"},
+		{url: "jdbc:postgresql://localhost.example.com:5432/test_db", expected: "jdbc:postgresql://localhost.example.com:5432/test_db"},
+		{url: "jdbc:postgresql://pgdb:5432/localhost", expected: "jdbc:postgresql://pgdb:5432/localhost"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.url, func(tt *testing.T) {
+			testCase := testCase
+
+			req := testcontainers.GenericContainerRequest{}
+			require.NoError(tt, WithDatabaseUrl(testCase.url)(&req))
+			require.NoError(tt, withHostAccess(&req, HostAccessGateway))
+			require.Equal(tt, testCase.expected, req.Env[flywayEnvUrlKey])
+
+			hostConfig := &container.HostConfig{}
+			req.HostConfigModifier(hostConfig)
+			require.Equal(tt, []string{"host.docker.internal:host-gateway"}, hostConfig.ExtraHosts)
+		})
+	}
+}
+
+func TestFlyway_withHostAccessNetwork(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithDatabaseUrl("jdbc:postgresql://localhost:5432/test_db")(&req))
+	require.NoError(t, withHostAccess(&req, HostAccessNetwork))
+	require.Equal(t, "jdbc:postgresql://localhost:5432/test_db", req.Env[flywayEnvUrlKey])
+
+	hostConfig := &container.HostConfig{}
+	req.HostConfigModifier(hostConfig)
+	require.Equal(t, container.NetworkMode("host"), hostConfig.NetworkMode)
+
+	req = testcontainers.GenericContainerRequest{}
+	req.Networks = []string{"test-nw"}
+	require.Error(t, withHostAccess(&req, HostAccessNetwork))
+}
+
+func TestFlyway_withHostAccessUnsupported(t *testing.T) {
+	require.Error(t, WithHostAccess("bridge")(&options{}))
+}
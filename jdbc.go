@@ -0,0 +1,291 @@
+package flyway
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const jdbcScheme = "jdbc:"
+
+// jdbcFormat is how the host, port, database and parameters are laid out in the urls of a subprotocol
+type jdbcFormat int
+
+const (
+	opaqueFormat       jdbcFormat = iota // not decomposed, e.g. jdbc:h2:mem:test
+	hierarchicalFormat                   // jdbc:<subprotocol>://host:port/database?key=value
+	sqlserverFormat                      // jdbc:sqlserver://host:port;databaseName=database;key=value
+	oracleFormat                         // jdbc:oracle:thin:@//host:port/service?key=value
+)
+
+// jdbcSubprotocols maps the subprotocols of the databases supported by flyway to the format of their urls, the other
+// subprotocols are rejected unless a custom driver is added, see WithCustomDriver
+var jdbcSubprotocols = map[string]jdbcFormat{
+	"bigquery":      opaqueFormat,
+	"clickhouse":    hierarchicalFormat,
+	"cloudspanner":  opaqueFormat,
+	"databricks":    opaqueFormat,
+	"db2":           hierarchicalFormat,
+	"derby":         opaqueFormat,
+	"firebirdsql":   opaqueFormat,
+	"h2":            opaqueFormat,
+	"hsqldb":        opaqueFormat,
+	"informix-sqli": opaqueFormat,
+	"jtds":          opaqueFormat,
+	"mariadb":       hierarchicalFormat,
+	"mysql":         hierarchicalFormat,
+	"oracle":        oracleFormat,
+	"postgresql":    hierarchicalFormat,
+	"redshift":      hierarchicalFormat,
+	"sap":           opaqueFormat,
+	"singlestore":   hierarchicalFormat,
+	"snowflake":     opaqueFormat,
+	"sqlite":        opaqueFormat,
+	"sqlserver":     sqlserverFormat,
+}
+
+// jdbcSubprotocolTypos maps the subprotocols commonly mistaken for supported ones
+var jdbcSubprotocolTypos = map[string]string{
+	"cockroach":   "postgresql",
+	"cockroachdb": "postgresql",
+	"maria":       "mariadb",
+	"mssql":       "sqlserver",
+	"pg":          "postgresql",
+	"pgsql":       "postgresql",
+	"postgres":    "postgresql",
+	"sqlite3":     "sqlite",
+}
+
+// JDBCURL is the jdbc url of a database supported by flyway. The host, port, database and parameters are only
+// decomposed for the subprotocols with a host based url, e.g. postgresql, mysql, sqlserver or oracle thin.
+type JDBCURL struct {
+	Subprotocol string
+	Host        string
+	Port        string
+	Database    string
+	Params      map[string]string
+
+	// opaque is the part after the subprotocol of the urls which are not decomposed
+	opaque string
+}
+
+// PostgresURL returns the url of a postgres database, which is also used for cockroachdb
+func PostgresURL(host, port, database string) *JDBCURL {
+	return &JDBCURL{Subprotocol: "postgresql", Host: host, Port: port, Database: database}
+}
+
+// MySQLURL returns the url of a mysql database
+func MySQLURL(host, port, database string) *JDBCURL {
+	return &JDBCURL{Subprotocol: "mysql", Host: host, Port: port, Database: database}
+}
+
+// MariaDBURL returns the url of a mariadb database
+func MariaDBURL(host, port, database string) *JDBCURL {
+	return &JDBCURL{Subprotocol: "mariadb", Host: host, Port: port, Database: database}
+}
+
+// SQLServerURL returns the url of a sql server database
+func SQLServerURL(host, port, database string) *JDBCURL {
+	return &JDBCURL{Subprotocol: "sqlserver", Host: host, Port: port, Database: database}
+}
+
+// OracleURL returns the thin driver url of an oracle database, identified by its service name
+func OracleURL(host, port, service string) *JDBCURL {
+	return &JDBCURL{Subprotocol: "oracle", Host: host, Port: port, Database: service}
+}
+
+// ClickHouseURL returns the url of a clickhouse database
+func ClickHouseURL(host, port, database string) *JDBCURL {
+	return &JDBCURL{Subprotocol: "clickhouse", Host: host, Port: port, Database: database}
+}
+
+// WithParam sets a parameter of the url, e.g. sslmode=disable
+func (u *JDBCURL) WithParam(key, value string) *JDBCURL {
+	if u.Params == nil {
+		u.Params = map[string]string{}
+	}
+	u.Params[key] = value
+	return u
+}
+
+// String formats the url, the parameters are ordered by key
+func (u *JDBCURL) String() string {
+	format := jdbcSubprotocols[u.Subprotocol]
+	if u.opaque != "" || format == opaqueFormat {
+		return jdbcScheme + u.Subprotocol + ":" + u.opaque
+	}
+
+	address := u.Host
+	if u.Port != "" {
+		address = net.JoinHostPort(u.Host, u.Port)
+	}
+
+	keys := make([]string, 0, len(u.Params))
+	for key := range u.Params {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	b.WriteString(jdbcScheme + u.Subprotocol + ":")
+
+	switch format {
+	case sqlserverFormat:
+		b.WriteString("//" + address)
+		if u.Database != "" {
+			b.WriteString(";databaseName=" + u.Database)
+		}
+		for _, key := range keys {
+			b.WriteString(";" + key + "=" + u.Params[key])
+		}
+		return b.String()
+	case oracleFormat:
+		b.WriteString("thin:@//" + address)
+	default:
+		b.WriteString("//" + address)
+	}
+
+	if u.Database != "" {
+		b.WriteString("/" + u.Database)
+	}
+	if len(keys) > 0 {
+		values := url.Values{}
+		for _, key := range keys {
+			values.Set(key, u.Params[key])
+		}
+		b.WriteString("?" + values.Encode())
+	}
+	return b.String()
+}
+
+// driverProtocolRegexp matches the driver specific protocols of host based urls, e.g. loadbalance:// for mysql
+var driverProtocolRegexp = regexp.MustCompile(`^[a-z]+://`)
+
+// postgresDatabaseRegexp matches the short form of the postgres urls, e.g. jdbc:postgresql:database
+var postgresDatabaseRegexp = regexp.MustCompile(`^[^:/?]+(\?.*)?$`)
+
+// WithCustomDriver accepts the database urls of a jdbc driver added to the flyway image, e.g. yugabytedb, whose
+// subprotocol is not one of the databases supported by flyway. Its urls are left to the driver.
+func WithCustomDriver(subprotocol string) Option {
+	return func(o *options) error {
+		if subprotocol == "" || strings.ContainsAny(subprotocol, ":/") {
+			return fmt.Errorf("invalid driver subprotocol: %q", subprotocol)
+		}
+
+		o.customDrivers = append(o.customDrivers, subprotocol)
+		return nil
+	}
+}
+
+// ParseJDBCURL parses the url of a database supported by flyway, it fails when the scheme or the subprotocol are
+// not the ones flyway expects. The driver specific forms, e.g. jdbc:postgresql:database, are kept opaque.
+func ParseJDBCURL(raw string) (*JDBCURL, error) {
+	return parseJDBCURL(raw, nil)
+}
+
+// parseJDBCURL parses the url of a database supported by flyway or by one of the custom drivers, whose urls are
+// kept opaque
+func parseJDBCURL(raw string, customDrivers []string) (*JDBCURL, error) {
+	rest, ok := strings.CutPrefix(raw, jdbcScheme)
+	if !ok {
+		return nil, fmt.Errorf("expected the %q scheme, e.g. jdbc:postgresql://host:5432/database", jdbcScheme)
+	}
+
+	subprotocol, rest, ok := strings.Cut(rest, ":")
+	if !ok || subprotocol == "" {
+		return nil, fmt.Errorf("missing subprotocol, e.g. jdbc:postgresql://host:5432/database")
+	}
+
+	u := &JDBCURL{Subprotocol: subprotocol}
+	opaque := &JDBCURL{Subprotocol: subprotocol, opaque: rest}
+	if slices.Contains(customDrivers, subprotocol) {
+		return opaque, nil
+	}
+
+	format, ok := jdbcSubprotocols[subprotocol]
+	if !ok {
+		if suggestion, ok := jdbcSubprotocolTypos[strings.ToLower(subprotocol)]; ok {
+			return nil, fmt.Errorf("unsupported subprotocol %q, did you mean %q?", subprotocol, suggestion)
+		}
+		return nil, fmt.Errorf("unsupported subprotocol %q, expected one of %s, or please use flyway.WithCustomDriver() for a driver added to the image",
+			subprotocol, strings.Join(supportedSubprotocols(), ", "))
+	}
+
+	switch format {
+	case opaqueFormat:
+		return opaque, nil
+	case oracleFormat:
+		if rest, ok = strings.CutPrefix(rest, "thin:@//"); !ok {
+			// sid and tns descriptor urls are not decomposed
+			return opaque, nil
+		}
+	default:
+		if rest, ok = strings.CutPrefix(rest, "//"); !ok {
+			// e.g. jdbc:mysql:loadbalance://host1,host2/database or jdbc:postgresql:database
+			if driverProtocolRegexp.MatchString(rest) || (subprotocol == "postgresql" && postgresDatabaseRegexp.MatchString(rest)) {
+				return opaque, nil
+			}
+			return nil, fmt.Errorf("expected a host based url for subprotocol %q, e.g. %s", subprotocol, exampleURL(subprotocol))
+		}
+	}
+
+	var address string
+	if jdbcSubprotocols[subprotocol] == sqlserverFormat {
+		props := strings.Split(rest, ";")
+		address = props[0]
+		for _, prop := range props[1:] {
+			key, value, _ := strings.Cut(prop, "=")
+			if key == "" {
+				continue
+			}
+			if strings.EqualFold(key, "databaseName") || strings.EqualFold(key, "database") {
+				u.Database = value
+				continue
+			}
+			u.WithParam(key, value)
+		}
+	} else {
+		var query string
+		address, query, _ = strings.Cut(rest, "?")
+		address, u.Database, _ = strings.Cut(address, "/")
+
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameters for subprotocol %q: %w", subprotocol, err)
+		}
+		for key := range values {
+			u.WithParam(key, values.Get(key))
+		}
+	}
+
+	if strings.ContainsAny(address, "@,") {
+		// credentials and multiple hosts are not decomposed
+		return opaque, nil
+	}
+
+	u.Host = address
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		u.Host, u.Port = host, port
+	}
+	if u.Host == "" {
+		// the driver default host, e.g. jdbc:postgresql:///database, or a serverName property of sql server
+		return opaque, nil
+	}
+	return u, nil
+}
+
+func supportedSubprotocols() []string {
+	subprotocols := make([]string, 0, len(jdbcSubprotocols))
+	for subprotocol := range jdbcSubprotocols {
+		subprotocols = append(subprotocols, subprotocol)
+	}
+	slices.Sort(subprotocols)
+	return subprotocols
+}
+
+func exampleURL(subprotocol string) string {
+	return (&JDBCURL{Subprotocol: subprotocol, Host: "host", Port: "1234", Database: "database"}).String()
+}
@@ -0,0 +1,230 @@
+package flyway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// LogLevel is the level of a line logged by flyway
+type LogLevel string
+
+const (
+	LogLevelDebug LogLevel = "DEBUG"
+	LogLevelInfo  LogLevel = "INFO"
+	LogLevelWarn  LogLevel = "WARN"
+	LogLevelError LogLevel = "ERROR"
+
+	// commandStartPrefix starts the output of each command run by flyway
+	commandStartPrefix = "Database: "
+)
+
+// logLevelPrefixes maps the prefixes flyway logs before the messages to their level, info messages have none
+var logLevelPrefixes = map[string]LogLevel{
+	"DEBUG: ":   LogLevelDebug,
+	"WARNING: ": LogLevelWarn,
+	"ERROR: ":   LogLevelError,
+}
+
+// LogEntry is a line logged by flyway
+type LogEntry struct {
+	Level LogLevel
+	// Command is the command which logged the line, empty for the lines logged before the first command
+	Command Command
+	// Message is the line without its level prefix
+	Message string
+}
+
+// Logs is the output of the flyway container, split in entries
+type Logs struct {
+	Entries []LogEntry
+}
+
+// Level returns the entries of a level
+func (l *Logs) Level(level LogLevel) []LogEntry {
+	var entries []LogEntry
+	for _, entry := range l.Entries {
+		if entry.Level == level {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Command returns the entries logged by a command
+func (l *Logs) Command(cmd Command) []LogEntry {
+	var entries []LogEntry
+	for _, entry := range l.Entries {
+		if entry.Command == cmd {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// FlywayLogs returns the output of the container split in entries, by level and command, whereas Logs returns the
+// raw output. The commands are only known with the default text output type.
+func (c *FlywayContainer) FlywayLogs(ctx context.Context) (*Logs, error) {
+	logs, err := c.Container.Logs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer logs.Close()
+
+	return parseLogs(logs, c.commands)
+}
+
+func parseLogs(r io.Reader, cmds []Command) (*Logs, error) {
+	parser := &logParser{commands: cmds}
+	logs := &Logs{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if entry, ok := parser.parse(scanner.Text()); ok {
+			logs.Entries = append(logs.Entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read flyway logs: %w", err)
+	}
+	return logs, nil
+}
+
+// logParser parses the lines logged by flyway in order, it keeps track of the current command and error
+type logParser struct {
+	commands []Command
+	// started is the number of commands started so far
+	started int
+	// inError is set within the multi-line details of an error, up to the next blank line
+	inError bool
+}
+
+// parse returns the entry of a line, false for blank lines
+func (p *logParser) parse(line string) (LogEntry, bool) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		p.inError = false
+		return LogEntry{}, false
+	}
+
+	entry := LogEntry{Level: LogLevelInfo, Message: line}
+	for prefix, level := range logLevelPrefixes {
+		if message, ok := strings.CutPrefix(line, prefix); ok {
+			entry.Level, entry.Message = level, message
+			break
+		}
+	}
+
+	switch {
+	case entry.Level == LogLevelError:
+		p.inError = true
+	case entry.Level == LogLevelInfo && p.inError:
+		entry.Level = LogLevelError
+	case entry.Level == LogLevelInfo && strings.HasPrefix(line, commandStartPrefix) && p.started < len(p.commands):
+		p.started++
+	}
+
+	if p.started > 0 {
+		entry.Command = p.commands[p.started-1]
+	}
+	return entry, true
+}
+
+// Logger receives the lines logged by flyway while it runs, e.g. a testing.TB
+type Logger interface {
+	Logf(format string, args ...any)
+}
+
+// WithLogger forwards the lines logged by flyway to a logger, e.g. a testing.TB, while it runs
+func WithLogger(logger Logger) Option {
+	return func(o *options) error {
+		if logger == nil {
+			return errors.New("missing logger")
+		}
+
+		o.notExecOptions = append(o.notExecOptions, "flyway.WithLogger()")
+		o.logHandlers = append(o.logHandlers, func(entry LogEntry) {
+			if entry.Command == "" {
+				logger.Logf("flyway %s: %s", entry.Level, entry.Message)
+				return
+			}
+			logger.Logf("flyway %s %s: %s", entry.Command, entry.Level, entry.Message)
+		})
+		return nil
+	}
+}
+
+// WithSlogLogger forwards the lines logged by flyway to a structured logger while it runs, at the matching level
+func WithSlogLogger(logger *slog.Logger) Option {
+	return func(o *options) error {
+		if logger == nil {
+			return errors.New("missing logger")
+		}
+
+		o.notExecOptions = append(o.notExecOptions, "flyway.WithSlogLogger()")
+		o.logHandlers = append(o.logHandlers, func(entry LogEntry) {
+			level := slog.LevelInfo
+			switch entry.Level {
+			case LogLevelDebug:
+				level = slog.LevelDebug
+			case LogLevelWarn:
+				level = slog.LevelWarn
+			case LogLevelError:
+				level = slog.LevelError
+			}
+			logger.Log(context.Background(), level, entry.Message, slog.String("command", string(entry.Command)))
+		})
+		return nil
+	}
+}
+
+// withLogHandlers follows the logs of the container, and passes each entry to the handlers
+func withLogHandlers(req *testcontainers.GenericContainerRequest, cmds []Command, handlers []func(LogEntry)) {
+	if req.LogConsumerCfg == nil {
+		req.LogConsumerCfg = &testcontainers.LogConsumerConfig{}
+	}
+
+	req.LogConsumerCfg.Consumers = append(req.LogConsumerCfg.Consumers, &logConsumer{
+		parser:   &logParser{commands: cmds},
+		handlers: handlers,
+	})
+}
+
+// logConsumer splits the logs of the container in lines, which may be received in several chunks
+type logConsumer struct {
+	mu       sync.Mutex
+	parser   *logParser
+	handlers []func(LogEntry)
+	pending  []byte
+}
+
+// Accept implements testcontainers.LogConsumer
+func (c *logConsumer) Accept(log testcontainers.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending = append(c.pending, log.Content...)
+	for {
+		i := bytes.IndexByte(c.pending, '\n')
+		if i < 0 {
+			return
+		}
+
+		line := string(c.pending[:i])
+		c.pending = c.pending[i+1:]
+
+		if entry, ok := c.parser.parse(line); ok {
+			for _, handle := range c.handlers {
+				handle(entry)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package flyway
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestFlyway_parseLogs(t *testing.T) {
+	output := "WARNING: Connection retries are deprecated\n" + infoOutput
+
+	logs, err := parseLogs(strings.NewReader(output), []Command{CommandMigrate, CommandInfo})
+	require.NoError(t, err)
+
+	require.Equal(t, LogEntry{Level: LogLevelWarn, Message: "Connection retries are deprecated"}, logs.Entries[0])
+	require.Len(t, logs.Level(LogLevelWarn), 1)
+	require.Empty(t, logs.Level(LogLevelError))
+
+	migrate := logs.Command(CommandMigrate)
+	require.Equal(t, "Database: jdbc:postgresql://pgdb:5432/test_db (PostgreSQL 16.3)", migrate[0].Message)
+	require.Equal(t, `Successfully applied 2 migrations to schema "public", now at version v1.2 (execution time 00:00.031s)`, migrate[len(migrate)-1].Message)
+
+	info := logs.Command(CommandInfo)
+	require.Equal(t, "Schema version: 1.2", info[1].Message)
+}
+
+func TestFlyway_parseLogsError(t *testing.T) {
+	logs, err := parseLogs(strings.NewReader(failedMigrationOutput), []Command{CommandMigrate, CommandInfo})
+	require.NoError(t, err)
+
+	errorEntries := logs.Level(LogLevelError)
+	require.Equal(t, `Migration of schema "public" to version "1.2 - alter table stuff" failed! Changes successfully rolled back.`, errorEntries[0].Message)
+	require.Equal(t, "ADD COLUMN created_timestamp TIMESTAMP", errorEntries[len(errorEntries)-1].Message)
+	for _, entry := range errorEntries {
+		require.Equal(t, CommandMigrate, entry.Command)
+	}
+	require.Empty(t, logs.Command(CommandInfo))
+}
+
+// testLogger records the lines it receives
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Logf(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestFlyway_withLogger(t *testing.T) {
+	logger := &testLogger{}
+	var buffer bytes.Buffer
+
+	settings := defaultOptions()
+	require.NoError(t, WithLogger(logger)(&settings))
+	require.NoError(t, WithSlogLogger(slog.New(slog.NewTextHandler(&buffer, nil)))(&settings))
+
+	req := testcontainers.GenericContainerRequest{}
+	withLogHandlers(&req, settings.commands, settings.logHandlers)
+	require.Len(t, req.LogConsumerCfg.Consumers, 1)
+
+	// the lines may be split across chunks
+	consumer := req.LogConsumerCfg.Consumers[0]
+	consumer.Accept(testcontainers.Log{Content: []byte("WARNING: Connection retries are dep")})
+	consumer.Accept(testcontainers.Log{Content: []byte("recated\nDatabase: jdbc:postgresql://pgdb:5432/test_db\n\nERROR: boom\n")})
+
+	require.Equal(t, []string{
+		"flyway WARN: Connection retries are deprecated",
+		"flyway migrate INFO: Database: jdbc:postgresql://pgdb:5432/test_db",
+		"flyway migrate ERROR: boom",
+	}, logger.lines)
+	require.Contains(t, buffer.String(), `level=WARN msg="Connection retries are deprecated"`)
+	require.Contains(t, buffer.String(), `level=ERROR msg=boom command=migrate`)
+}
@@ -0,0 +1,261 @@
+package flyway
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	defaultMigrationFileMode int64 = 0o644
+	filesystemLocationPrefix       = "filesystem:"
+	locationsSeparator             = ","
+)
+
+var migrationVersionRegexp = regexp.MustCompile(`^\d+([._]\d+)*$`)
+
+// Migration is a versioned migration declared in code, e.g. to build a schema in a table test
+type Migration struct {
+	Version     string // e.g. 1.1, dots or underscores separate the version parts
+	Description string // e.g. create stuff, spaces are replaced with underscores in the file name
+	SQL         string
+}
+
+// filename returns the name of the migration, following the flyway V<version>__<description>.sql naming convention
+func (m Migration) filename() string {
+	return fmt.Sprintf("V%s__%s.sql", m.Version, strings.ReplaceAll(m.Description, " ", "_"))
+}
+
+func (m Migration) validate() error {
+	if !migrationVersionRegexp.MatchString(m.Version) {
+		return fmt.Errorf("invalid migration version %q: expected digits separated by dots or underscores", m.Version)
+	}
+	if strings.TrimSpace(m.Description) == "" {
+		return fmt.Errorf("missing description for migration %s", m.Version)
+	}
+	if strings.ContainsAny(m.Description, "/\\") {
+		return fmt.Errorf("invalid description %q for migration %s: path separators are not allowed", m.Description, m.Version)
+	}
+	if strings.TrimSpace(m.SQL) == "" {
+		return fmt.Errorf("missing sql for migration %s", m.Version)
+	}
+	return nil
+}
+
+// WithInlineMigrations copies the given migrations to the container, each one as a file named following the
+// flyway naming convention
+func WithInlineMigrations(migrations ...Migration) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if len(migrations) == 0 {
+			return errors.New("missing migrations: no inline migrations provided")
+		}
+
+		versions := make(map[string]bool, len(migrations))
+		files := make([]testcontainers.ContainerFile, 0, len(migrations))
+		for _, migration := range migrations {
+			if err := migration.validate(); err != nil {
+				return err
+			}
+			if versions[migration.Version] {
+				return fmt.Errorf("duplicate migration version %s", migration.Version)
+			}
+			versions[migration.Version] = true
+
+			files = append(files, testcontainers.ContainerFile{
+				Reader:            strings.NewReader(migration.SQL),
+				ContainerFilePath: path.Join(DefaultMigrationsPath, migration.filename()),
+				FileMode:          defaultMigrationFileMode,
+			})
+		}
+		if err := withFiles(req, files...); err != nil {
+			return err
+		}
+
+		return withLocation(req, DefaultMigrationsPath)
+	}
+}
+
+// WithMigrations copies the migrations found at absHostFilePath, a directory or a single file, to the
+// DefaultMigrationsPath location of the container
+func WithMigrations(absHostFilePath string) testcontainers.CustomizeRequestOption {
+	return WithMigrationsLocation(absHostFilePath, DefaultMigrationsPath)
+}
+
+// WithMigrationsLocation copies the migrations found at absHostFilePath, a directory or a single file, to
+// containerPath and adds it to the flyway locations. It can be used several times to provide migrations
+// from several directories, e.g. shared and per service migrations.
+func WithMigrationsLocation(absHostFilePath, containerPath string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if !path.IsAbs(containerPath) {
+			return fmt.Errorf("invalid migrations location %q: the container path must be absolute", containerPath)
+		}
+
+		files, err := hostMigrationFiles(absHostFilePath, containerPath)
+		if err != nil {
+			return err
+		}
+		if err := withFiles(req, files...); err != nil {
+			return err
+		}
+
+		return withLocation(req, containerPath)
+	}
+}
+
+// WithMigrationsFS copies the migrations found under root in fsys, e.g. an embed.FS, to the container
+func WithMigrationsFS(fsys fs.FS, root string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		files, err := migrationFiles(fsys, root, DefaultMigrationsPath)
+		if err != nil {
+			return err
+		}
+		if err := withFiles(req, files...); err != nil {
+			return err
+		}
+
+		return withLocation(req, DefaultMigrationsPath)
+	}
+}
+
+// withFiles adds the files to the request, keeping the files added by other options. The request is left
+// unchanged if a container path is already used.
+func withFiles(req *testcontainers.GenericContainerRequest, files ...testcontainers.ContainerFile) error {
+	all := append(append([]testcontainers.ContainerFile{}, req.Files...), files...)
+	if err := checkDuplicateFiles(all); err != nil {
+		return err
+	}
+
+	req.Files = all
+	return nil
+}
+
+func checkDuplicateFiles(files []testcontainers.ContainerFile) error {
+	paths := make(map[string]bool, len(files))
+	for _, file := range files {
+		containerPath := path.Clean(file.ContainerFilePath)
+		if paths[containerPath] {
+			return fmt.Errorf("duplicate container file: %s is provided more than once", containerPath)
+		}
+		paths[containerPath] = true
+	}
+	return nil
+}
+
+// withLocation adds the container path to the flyway locations, unless it is already listed
+func withLocation(req *testcontainers.GenericContainerRequest, containerPath string) error {
+	location := filesystemLocationPrefix + containerPath
+
+	locations := splitLocations(req.Env[flywayEnvLocationsKey])
+	for _, existing := range locations {
+		if existing == location {
+			return nil
+		}
+	}
+
+	return withSetting(flywayEnvLocationsKey, append(locations, location))(req)
+}
+
+func splitLocations(locations string) []string {
+	var split []string
+	for _, location := range strings.Split(locations, locationsSeparator) {
+		if location = strings.TrimSpace(location); location != "" {
+			split = append(split, location)
+		}
+	}
+	return split
+}
+
+// hostMigrationFiles returns a container file per regular file found at hostPath, keeping the directory
+// layout below hostPath. Directories are not copied as a whole, as their content would be copied under
+// their own name rather than under containerPath.
+func hostMigrationFiles(hostPath, containerPath string) ([]testcontainers.ContainerFile, error) {
+	info, err := os.Stat(hostPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations from %s: %w", hostPath, err)
+	}
+	if !info.IsDir() {
+		return []testcontainers.ContainerFile{{
+			HostFilePath:      hostPath,
+			ContainerFilePath: path.Join(containerPath, filepath.Base(hostPath)),
+			FileMode:          defaultMigrationFileMode,
+		}}, nil
+	}
+
+	var files []testcontainers.ContainerFile
+	err = filepath.WalkDir(hostPath, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		relative, err := filepath.Rel(hostPath, name)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, testcontainers.ContainerFile{
+			HostFilePath:      name,
+			ContainerFilePath: path.Join(containerPath, filepath.ToSlash(relative)),
+			FileMode:          defaultMigrationFileMode,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations from %s: %w", hostPath, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("missing migrations: no files found in %s", hostPath)
+	}
+	return files, nil
+}
+
+// migrationFiles returns a container file per regular file found under root in fsys, keeping the
+// directory layout below root
+func migrationFiles(fsys fs.FS, root, containerPath string) ([]testcontainers.ContainerFile, error) {
+	var files []testcontainers.ContainerFile
+
+	err := fs.WalkDir(fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		relative := strings.TrimPrefix(name, root)
+		if root == "." {
+			relative = name
+		}
+
+		files = append(files, testcontainers.ContainerFile{
+			Reader:            bytes.NewReader(content),
+			ContainerFilePath: path.Join(containerPath, relative),
+			FileMode:          defaultMigrationFileMode,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations from %s: %w", root, err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("missing migrations: no files found in %s", root)
+	}
+	return files, nil
+}
@@ -0,0 +1,162 @@
+package flyway
+
+import (
+	"io"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestFlyway_withMigrationsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/V1.1__create_table_stuff.sql":       {Data: []byte("CREATE TABLE stuff (name TEXT);")},
+		"migrations/nested/V1.2__alter_table_stuff.sql": {Data: []byte("ALTER TABLE stuff ADD COLUMN id INT;")},
+		"other/V2__ignored.sql":                         {Data: []byte("DROP TABLE stuff;")},
+	}
+
+	req := testcontainers.GenericContainerRequest{}
+	err := WithMigrationsFS(fsys, "migrations")(&req)
+	require.NoError(t, err)
+
+	require.Equal(t, "filesystem:/flyway/sql", req.Env[flywayEnvLocationsKey])
+	require.Len(t, req.Files, 2)
+	require.Equal(t, "/flyway/sql/V1.1__create_table_stuff.sql", req.Files[0].ContainerFilePath)
+	require.Equal(t, "/flyway/sql/nested/V1.2__alter_table_stuff.sql", req.Files[1].ContainerFilePath)
+
+	content, err := io.ReadAll(req.Files[0].Reader)
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE stuff (name TEXT);", string(content))
+}
+
+func TestFlyway_withMigrationsFSInvalid(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/empty": {Mode: fs.ModeDir | 0o755},
+	}
+
+	req := testcontainers.GenericContainerRequest{}
+	require.Error(t, WithMigrationsFS(fsys, "missing")(&req))
+	require.Error(t, WithMigrationsFS(fsys, "migrations")(&req))
+	require.Empty(t, req.Files)
+}
+
+func TestFlyway_withInlineMigrations(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	err := WithInlineMigrations(
+		Migration{Version: "1.1", Description: "create stuff", SQL: "CREATE TABLE stuff (name TEXT);"},
+		Migration{Version: "1_2", Description: "alter stuff", SQL: "ALTER TABLE stuff ADD COLUMN id INT;"},
+	)(&req)
+	require.NoError(t, err)
+
+	require.Equal(t, "filesystem:/flyway/sql", req.Env[flywayEnvLocationsKey])
+	require.Len(t, req.Files, 2)
+	require.Equal(t, "/flyway/sql/V1.1__create_stuff.sql", req.Files[0].ContainerFilePath)
+	require.Equal(t, "/flyway/sql/V1_2__alter_stuff.sql", req.Files[1].ContainerFilePath)
+
+	content, err := io.ReadAll(req.Files[1].Reader)
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE stuff ADD COLUMN id INT;", string(content))
+}
+
+func TestFlyway_withInlineMigrationsInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{
+			name: "no migrations",
+		},
+		{
+			name:       "missing version",
+			migrations: []Migration{{Description: "create stuff", SQL: "SELECT 1;"}},
+		},
+		{
+			name:       "invalid version",
+			migrations: []Migration{{Version: "v1", Description: "create stuff", SQL: "SELECT 1;"}},
+		},
+		{
+			name:       "missing description",
+			migrations: []Migration{{Version: "1", SQL: "SELECT 1;"}},
+		},
+		{
+			name:       "path in description",
+			migrations: []Migration{{Version: "1", Description: "../stuff", SQL: "SELECT 1;"}},
+		},
+		{
+			name:       "missing sql",
+			migrations: []Migration{{Version: "1", Description: "create stuff"}},
+		},
+		{
+			name: "duplicate version",
+			migrations: []Migration{
+				{Version: "1", Description: "create stuff", SQL: "SELECT 1;"},
+				{Version: "1", Description: "alter stuff", SQL: "SELECT 2;"},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			req := testcontainers.GenericContainerRequest{}
+			err := WithInlineMigrations(testCase.migrations...)(&req)
+			require.Error(tt, err, "expected error")
+			require.Empty(tt, req.Files, "expected no files")
+		})
+	}
+}
+
+func TestFlyway_withMigrationsLocation(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	err := WithMigrations(filepath.Join("testdata", DefaultMigrationsPath))(&req)
+	require.NoError(t, err)
+	err = WithMigrationsLocation(filepath.Join("testdata", DefaultMigrationsPath, "V1.1__create_table_stuff.sql"), "/flyway/shared")(&req)
+	require.NoError(t, err)
+	err = WithInlineMigrations(Migration{Version: "2", Description: "drop stuff", SQL: "DROP TABLE stuff;"})(&req)
+	require.NoError(t, err)
+
+	require.Equal(t, "filesystem:/flyway/sql,filesystem:/flyway/shared", req.Env[flywayEnvLocationsKey])
+	require.Len(t, req.Files, 4)
+	require.Equal(t, "/flyway/sql/V1.1__create_table_stuff.sql", req.Files[0].ContainerFilePath)
+	require.Equal(t, "/flyway/sql/V1.2__alter_table_stuff.sql", req.Files[1].ContainerFilePath)
+	require.Equal(t, "/flyway/shared/V1.1__create_table_stuff.sql", req.Files[2].ContainerFilePath)
+}
+
+func TestFlyway_withMigrationsLocationInvalid(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.Error(t, WithMigrationsLocation(filepath.Join("testdata", DefaultMigrationsPath), "flyway/sql")(&req))
+	require.Error(t, WithMigrationsLocation(filepath.Join("testdata", "missing"), DefaultMigrationsPath)(&req))
+	require.Empty(t, req.Files)
+}
+
+func TestFlyway_withMigrationsKeepsFiles(t *testing.T) {
+	driver := testcontainers.ContainerFile{
+		HostFilePath:      filepath.Join("testdata", "driver.jar"),
+		ContainerFilePath: "/flyway/drivers/driver.jar",
+	}
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Files: []testcontainers.ContainerFile{driver},
+		},
+	}
+
+	err := WithMigrations(filepath.Join("testdata", DefaultMigrationsPath))(&req)
+	require.NoError(t, err)
+	require.Len(t, req.Files, 3)
+	require.Equal(t, driver, req.Files[0])
+}
+
+func TestFlyway_withMigrationsDuplicateFiles(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	err := WithMigrations(filepath.Join("testdata", DefaultMigrationsPath))(&req)
+	require.NoError(t, err)
+
+	err = WithInlineMigrations(Migration{Version: "1.1", Description: "create table stuff", SQL: "SELECT 1;"})(&req)
+	require.EqualError(t, err, "duplicate container file: /flyway/sql/V1.1__create_table_stuff.sql is provided more than once")
+	require.Len(t, req.Files, 2, "expected the request to be left unchanged")
+}
@@ -0,0 +1,358 @@
+package flyway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// OutputType defines the format of the output printed by flyway
+type OutputType string
+
+const (
+	// OutputTypeText is the default, human-readable, flyway output
+	OutputTypeText OutputType = ""
+	// OutputTypeJSON makes flyway print the result of its commands as a json document
+	OutputTypeJSON OutputType = "json"
+
+	outputTypeFlag = "-outputType"
+)
+
+// ErrNoOutput is returned when the container output does not contain any flyway json output
+var ErrNoOutput = errors.New("no flyway json output found")
+
+// WithOutputType sets the format of the flyway output, see OutputTypeJSON to decode the command results
+func WithOutputType(outputType OutputType) Option {
+	return func(o *options) error {
+		switch outputType {
+		case OutputTypeText, OutputTypeJSON:
+			o.outputType = outputType
+			o.notExecOptions = append(o.notExecOptions, "flyway.WithOutputType()")
+			return nil
+		default:
+			return fmt.Errorf("unsupported output type: %q", outputType)
+		}
+	}
+}
+
+// OperationResult holds the fields shared by the results of all flyway commands
+type OperationResult struct {
+	FlywayVersion string   `json:"flywayVersion"`
+	Database      string   `json:"database"`
+	Warnings      []string `json:"warnings"`
+	Operation     string   `json:"operation"`
+}
+
+// MigrateResult is the result of the flyway migrate command
+type MigrateResult struct {
+	OperationResult
+	InitialSchemaVersion string          `json:"initialSchemaVersion"`
+	TargetSchemaVersion  string          `json:"targetSchemaVersion"`
+	SchemaName           string          `json:"schemaName"`
+	Migrations           []MigrateOutput `json:"migrations"`
+	MigrationsExecuted   int             `json:"migrationsExecuted"`
+	Success              bool            `json:"success"`
+}
+
+// MigrateOutput describes a migration applied by the flyway migrate command
+type MigrateOutput struct {
+	Category      string `json:"category"`
+	Version       string `json:"version"`
+	Description   string `json:"description"`
+	Type          string `json:"type"`
+	Filepath      string `json:"filepath"`
+	ExecutionTime int    `json:"executionTime"`
+}
+
+// InfoResult is the result of the flyway info command
+type InfoResult struct {
+	OperationResult
+	SchemaVersion   string       `json:"schemaVersion"`
+	SchemaName      string       `json:"schemaName"`
+	Migrations      []InfoOutput `json:"migrations"`
+	AllSchemasEmpty bool         `json:"allSchemasEmpty"`
+}
+
+// InfoOutput describes a migration listed by the flyway info command
+type InfoOutput struct {
+	Category       string `json:"category"`
+	Version        string `json:"version"`
+	Description    string `json:"description"`
+	Type           string `json:"type"`
+	InstalledOnUTC string `json:"installedOnUTC"`
+	InstalledOn    string `json:"installedOn"`
+	State          string `json:"state"`
+	Undoable       string `json:"undoable"`
+	Filepath       string `json:"filepath"`
+	UndoFilepath   string `json:"undoFilepath"`
+	InstalledBy    string `json:"installedBy"`
+	ExecutionTime  int    `json:"executionTime"`
+	Checksum       *int   `json:"checksum"`
+}
+
+// ValidateResult is the result of the flyway validate command
+type ValidateResult struct {
+	OperationResult
+	ErrorDetails         *ErrorDetails    `json:"errorDetails"`
+	InvalidMigrations    []ValidateOutput `json:"invalidMigrations"`
+	ValidationSuccessful bool             `json:"validationSuccessful"`
+	ValidateCount        int              `json:"validateCount"`
+}
+
+// ValidateOutput describes a migration which failed the flyway validate command
+type ValidateOutput struct {
+	Version      string        `json:"version"`
+	Description  string        `json:"description"`
+	Filepath     string        `json:"filepath"`
+	ErrorDetails *ErrorDetails `json:"errorDetails"`
+}
+
+// ErrorDetails describes why a flyway validation failed
+type ErrorDetails struct {
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// CleanResult is the result of the flyway clean command
+type CleanResult struct {
+	OperationResult
+	SchemasCleaned []string `json:"schemasCleaned"`
+	SchemasDropped []string `json:"schemasDropped"`
+}
+
+// RepairResult is the result of the flyway repair command
+type RepairResult struct {
+	OperationResult
+	RepairActions     []string       `json:"repairActions"`
+	MigrationsRemoved []RepairOutput `json:"migrationsRemoved"`
+	MigrationsDeleted []RepairOutput `json:"migrationsDeleted"`
+	MigrationsAligned []RepairOutput `json:"migrationsAligned"`
+}
+
+// RepairOutput describes a migration changed by the flyway repair command
+type RepairOutput struct {
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Filepath    string `json:"filepath"`
+}
+
+// BaselineResult is the result of the flyway baseline command
+type BaselineResult struct {
+	OperationResult
+	SuccessfullyBaselined bool   `json:"successfullyBaselined"`
+	BaselineVersion       string `json:"baselineVersion"`
+}
+
+// UndoResult is the result of the flyway undo command
+type UndoResult struct {
+	OperationResult
+	InitialSchemaVersion string       `json:"initialSchemaVersion"`
+	TargetSchemaVersion  string       `json:"targetSchemaVersion"`
+	SchemaName           string       `json:"schemaName"`
+	UndoneMigrations     []UndoOutput `json:"undoneMigrations"`
+	MigrationsUndone     int          `json:"migrationsUndone"`
+}
+
+// UndoOutput describes a migration undone by the flyway undo command
+type UndoOutput struct {
+	Version       string `json:"version"`
+	Description   string `json:"description"`
+	Filepath      string `json:"filepath"`
+	ExecutionTime int    `json:"executionTime"`
+}
+
+// ErrorOutput is the error reported by flyway when a command fails
+type ErrorOutput struct {
+	ErrorCode    string `json:"errorCode"`
+	SQLState     string `json:"sqlState"`
+	SQLErrorCode int    `json:"sqlErrorCode"`
+	Message      string `json:"message"`
+	StackTrace   string `json:"stackTrace"`
+	LineNumber   int    `json:"lineNumber"`
+	Path         string `json:"path"`
+}
+
+// Error implements the error interface
+func (e *ErrorOutput) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("flyway error: %s", e.Message)
+	}
+	return fmt.Sprintf("flyway error %s: %s", e.ErrorCode, e.Message)
+}
+
+// Output is the decoded json output of the flyway commands run by the container. If a command is run
+// more than once, its last result is kept.
+type Output struct {
+	Migrate  *MigrateResult
+	Info     *InfoResult
+	Validate *ValidateResult
+	Clean    *CleanResult
+	Repair   *RepairResult
+	Baseline *BaselineResult
+	Undo     *UndoResult
+	Error    *ErrorOutput
+}
+
+// Output returns the decoded json output of the flyway commands run by the container, it requires
+// the container to be run with WithOutputType(OutputTypeJSON)
+func (c *FlywayContainer) Output(ctx context.Context) (*Output, error) {
+	if c.outputType != OutputTypeJSON {
+		return nil, fmt.Errorf("%w: the container output type is not %s", ErrNoOutput, OutputTypeJSON)
+	}
+
+	return decodeOutput(ctx, c.Container)
+}
+
+func decodeOutput(ctx context.Context, container testcontainers.Container) (*Output, error) {
+	logs, err := container.Logs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer logs.Close()
+
+	return parseOutput(logs)
+}
+
+// parseOutput decodes the first json document found in r, any log line printed before it is ignored
+func parseOutput(r io.Reader) (*Output, error) {
+	var buffer bytes.Buffer
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.HasPrefix(strings.TrimSpace(line), "{") {
+			buffer.WriteString(line)
+			if _, err := io.Copy(&buffer, reader); err != nil {
+				return nil, fmt.Errorf("failed to read flyway output: %w", err)
+			}
+			break
+		}
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoOutput
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read flyway output: %w", err)
+		}
+	}
+
+	var document json.RawMessage
+	if err := json.NewDecoder(&buffer).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode flyway output: %w", err)
+	}
+
+	var composite struct {
+		Error             *ErrorOutput      `json:"error"`
+		IndividualResults []json.RawMessage `json:"individualResults"`
+	}
+	if err := json.Unmarshal(document, &composite); err != nil {
+		return nil, fmt.Errorf("failed to decode flyway output: %w", err)
+	}
+
+	results := composite.IndividualResults
+	if composite.Error == nil && len(results) == 0 {
+		// a single command prints its result directly, rather than as a composite result
+		results = []json.RawMessage{document}
+	}
+
+	output := &Output{Error: composite.Error}
+	for _, result := range results {
+		if err := output.add(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+func (o *Output) add(result json.RawMessage) error {
+	var base OperationResult
+	if err := json.Unmarshal(result, &base); err != nil {
+		return fmt.Errorf("failed to decode flyway result: %w", err)
+	}
+
+	var target any
+	switch Command(base.Operation) {
+	case CommandMigrate:
+		o.Migrate = &MigrateResult{}
+		target = o.Migrate
+	case CommandInfo:
+		o.Info = &InfoResult{}
+		target = o.Info
+	case CommandValidate:
+		o.Validate = &ValidateResult{}
+		target = o.Validate
+	case CommandClean:
+		o.Clean = &CleanResult{}
+		target = o.Clean
+	case CommandRepair:
+		o.Repair = &RepairResult{}
+		target = o.Repair
+	case CommandBaseline:
+		o.Baseline = &BaselineResult{}
+		target = o.Baseline
+	case CommandUndo:
+		o.Undo = &UndoResult{}
+		target = o.Undo
+	default:
+		return nil // results of other commands are not decoded
+	}
+
+	if err := json.Unmarshal(result, target); err != nil {
+		return fmt.Errorf("failed to decode flyway %s result: %w", base.Operation, err)
+	}
+	return nil
+}
+
+// report converts the info result into a migrations report
+func (r *InfoResult) report() (*Report, error) {
+	report := &Report{
+		SchemaVersion: r.SchemaVersion,
+		HistorySchema: r.SchemaName,
+		Migrations:    make([]MigrationInfo, 0, len(r.Migrations)),
+	}
+
+	for _, migration := range r.Migrations {
+		info := MigrationInfo{
+			Category:    migration.Category,
+			Version:     migration.Version,
+			Description: migration.Description,
+			Type:        migration.Type,
+			State:       migration.State,
+			Checksum:    migration.Checksum,
+		}
+
+		installedOn := migration.InstalledOnUTC
+		if installedOn == "" {
+			installedOn = migration.InstalledOn
+		}
+		if installedOn != "" {
+			t, err := parseInstalledOn(installedOn)
+			if err != nil {
+				return nil, fmt.Errorf("invalid installed on date %q for migration %q: %w", installedOn, migration.Version, err)
+			}
+			info.InstalledOn = t
+		}
+
+		report.Migrations = append(report.Migrations, info)
+	}
+
+	return report, nil
+}
+
+func parseInstalledOn(value string) (time.Time, error) {
+	var err error
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", infoInstalledOnLayout + ".999999999"} {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,132 @@
+package flyway
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const compositeOutput = `WARNING: Connection retries are deprecated
+{
+  "individualResults" : [ {
+    "initialSchemaVersion" : null,
+    "targetSchemaVersion" : "1.2",
+    "schemaName" : "public",
+    "migrations" : [ {
+      "category" : "Versioned",
+      "version" : "1.1",
+      "description" : "create table stuff",
+      "type" : "SQL",
+      "filepath" : "/flyway/sql/V1.1__create_table_stuff.sql",
+      "executionTime" : 6
+    }, {
+      "category" : "Versioned",
+      "version" : "1.2",
+      "description" : "alter table stuff",
+      "type" : "SQL",
+      "filepath" : "/flyway/sql/V1.2__alter_table_stuff.sql",
+      "executionTime" : 3
+    } ],
+    "migrationsExecuted" : 2,
+    "success" : true,
+    "flywayVersion" : "10.15.0",
+    "database" : "test_db",
+    "warnings" : [ ],
+    "operation" : "migrate"
+  }, {
+    "schemaVersion" : "1.2",
+    "schemaName" : "public",
+    "migrations" : [ {
+      "category" : "Versioned",
+      "version" : "1.1",
+      "description" : "create table stuff",
+      "type" : "SQL",
+      "installedOnUTC" : "2024-07-01T09:21:53.412Z",
+      "state" : "Success",
+      "undoable" : "No",
+      "filepath" : "/flyway/sql/V1.1__create_table_stuff.sql",
+      "installedBy" : "postgres",
+      "executionTime" : 6,
+      "checksum" : -1436211384
+    }, {
+      "category" : "Versioned",
+      "version" : "1.2",
+      "description" : "alter table stuff",
+      "type" : "SQL",
+      "installedOnUTC" : "",
+      "state" : "Pending",
+      "undoable" : "No",
+      "filepath" : "/flyway/sql/V1.2__alter_table_stuff.sql",
+      "installedBy" : "",
+      "executionTime" : 0
+    } ],
+    "allSchemasEmpty" : false,
+    "flywayVersion" : "10.15.0",
+    "database" : "test_db",
+    "warnings" : [ ],
+    "operation" : "info"
+  } ]
+}
+`
+
+func TestFlyway_parseOutput(t *testing.T) {
+	output, err := parseOutput(strings.NewReader(compositeOutput))
+	require.NoError(t, err)
+
+	require.Nil(t, output.Error)
+	require.NotNil(t, output.Migrate)
+	require.True(t, output.Migrate.Success)
+	require.Equal(t, 2, output.Migrate.MigrationsExecuted)
+	require.Equal(t, "public", output.Migrate.SchemaName)
+	require.Len(t, output.Migrate.Migrations, 2)
+
+	require.NotNil(t, output.Info)
+	require.Equal(t, "1.2", output.Info.SchemaVersion)
+
+	report, err := output.Info.report()
+	require.NoError(t, err)
+	require.Equal(t, "public", report.HistorySchema)
+	require.Len(t, report.Migrations, 2)
+	require.Equal(t, time.Date(2024, 7, 1, 9, 21, 53, 412000000, time.UTC), report.Migrations[0].InstalledOn)
+	require.NotNil(t, report.Migrations[0].Checksum)
+	require.Equal(t, -1436211384, *report.Migrations[0].Checksum)
+	require.True(t, report.Migrations[1].InstalledOn.IsZero())
+	require.Nil(t, report.Migrations[1].Checksum)
+}
+
+func TestFlyway_parseOutputSingleResult(t *testing.T) {
+	output, err := parseOutput(strings.NewReader(`{
+  "schemasCleaned" : [ "public" ],
+  "schemasDropped" : [ ],
+  "operation" : "clean"
+}`))
+	require.NoError(t, err)
+
+	require.NotNil(t, output.Clean)
+	require.Equal(t, []string{"public"}, output.Clean.SchemasCleaned)
+	require.Nil(t, output.Migrate)
+}
+
+func TestFlyway_parseOutputError(t *testing.T) {
+	output, err := parseOutput(strings.NewReader(`{
+  "error" : {
+    "errorCode" : "DB_CONNECTION",
+    "sqlState" : "08001",
+    "sqlErrorCode" : 0,
+    "message" : "Unable to obtain connection from database"
+  }
+}`))
+	require.NoError(t, err)
+
+	require.NotNil(t, output.Error)
+	require.Equal(t, "DB_CONNECTION", output.Error.ErrorCode)
+	require.Equal(t, "08001", output.Error.SQLState)
+	require.EqualError(t, output.Error, "flyway error DB_CONNECTION: Unable to obtain connection from database")
+}
+
+func TestFlyway_parseOutputMissing(t *testing.T) {
+	_, err := parseOutput(strings.NewReader(infoOutput))
+	require.ErrorIs(t, err, ErrNoOutput)
+}
@@ -0,0 +1,60 @@
+package flyway
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	flywayEnvPlaceholdersPrefix        = "FLYWAY_PLACEHOLDERS_"
+	flywayEnvPlaceholderPrefixKey      = "FLYWAY_PLACEHOLDER_PREFIX"
+	flywayEnvPlaceholderSuffixKey      = "FLYWAY_PLACEHOLDER_SUFFIX"
+	flywayEnvPlaceholderReplacementKey = "FLYWAY_PLACEHOLDER_REPLACEMENT"
+)
+
+// flyway reads placeholders from environment variables named after them, so their keys are limited to the
+// characters allowed in an environment variable name
+var placeholderEnvKeyRegexp = regexp.MustCompile(`^` + flywayEnvPlaceholdersPrefix + `[A-Z0-9_]+$`)
+
+// WithPlaceholders sets the values of the placeholders replaced in the migrations, e.g. ${schema}. Flyway reads
+// placeholders from the environment in lower case, so the keys are case-insensitive and must only contain
+// letters, digits and underscores.
+func WithPlaceholders(placeholders map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		env := make(map[string]string, len(placeholders))
+		for key, value := range placeholders {
+			envKey := placeholderEnvKey(key)
+			if _, ok := env[envKey]; ok {
+				return fmt.Errorf("duplicate placeholder %q: placeholder keys are case-insensitive", key)
+			}
+			if err := validateSetting(envKey, value); err != nil {
+				return err
+			}
+			env[envKey] = value
+		}
+
+		return testcontainers.WithEnv(env)(req)
+	}
+}
+
+// WithPlaceholderPrefix sets the prefix of the placeholders, defaults to ${
+func WithPlaceholderPrefix(prefix string) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvPlaceholderPrefixKey, prefix)
+}
+
+// WithPlaceholderSuffix sets the suffix of the placeholders, defaults to }
+func WithPlaceholderSuffix(suffix string) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvPlaceholderSuffixKey, suffix)
+}
+
+// WithPlaceholderReplacement enables or disables the replacement of the placeholders, enabled by default
+func WithPlaceholderReplacement(enabled bool) testcontainers.CustomizeRequestOption {
+	return withSetting(flywayEnvPlaceholderReplacementKey, enabled)
+}
+
+func placeholderEnvKey(key string) string {
+	return flywayEnvPlaceholdersPrefix + strings.ToUpper(key)
+}
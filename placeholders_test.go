@@ -0,0 +1,73 @@
+package flyway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestFlyway_withPlaceholders(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	err := WithPlaceholders(map[string]string{
+		"schema":   "stuff",
+		"APP_USER": "app",
+	})(&req)
+	require.NoError(t, err)
+
+	require.Equal(t, "stuff", req.Env["FLYWAY_PLACEHOLDERS_SCHEMA"])
+	require.Equal(t, "app", req.Env["FLYWAY_PLACEHOLDERS_APP_USER"])
+	require.NoError(t, parseSettings(req.Env, nil))
+}
+
+func TestFlyway_withPlaceholdersDuplicate(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	err := WithPlaceholders(map[string]string{
+		"schema": "stuff",
+		"Schema": "other",
+	})(&req)
+	require.Error(t, err)
+	require.Empty(t, req.Env)
+}
+
+func TestFlyway_withPlaceholdersInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  testcontainers.CustomizeRequestOption
+	}{
+		{
+			name: "empty key",
+			opt:  WithPlaceholders(map[string]string{"": "stuff"}),
+		},
+		{
+			name: "invalid key",
+			opt:  WithPlaceholders(map[string]string{"app-user": "app"}),
+		},
+		{
+			name: "empty prefix",
+			opt:  WithPlaceholderPrefix(""),
+		},
+		{
+			name: "empty suffix",
+			opt:  WithPlaceholderSuffix(""),
+		},
+		{
+			name: "invalid replacement",
+			opt:  testcontainers.WithEnv(map[string]string{flywayEnvPlaceholderReplacementKey: "yes please"}),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			req := testcontainers.GenericContainerRequest{}
+			err := testCase.opt(&req)
+			if err == nil {
+				err = parseSettings(req.Env, nil)
+			}
+			require.Error(tt, err, "expected error")
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package flyway
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// MigrationStateSuccess is the state of a migration which has been applied successfully
+	MigrationStateSuccess = "Success"
+	// MigrationStatePending is the state of a migration which has not been applied yet
+	MigrationStatePending = "Pending"
+	// MigrationStateFailed is the state of a migration which failed to apply
+	MigrationStateFailed = "Failed"
+
+	infoSchemaVersionPrefix = "Schema version:"
+	infoEmptySchemaVersion  = "<< Empty Schema >>"
+	infoInstalledOnLayout   = "2006-01-02 15:04:05"
+)
+
+var (
+	infoHistoryTableRegexp  = regexp.MustCompile(`[Ss]chema [Hh]istory table "([^"]+)"\."[^"]+"`)
+	infoCurrentSchemaRegexp = regexp.MustCompile(`Current version of schema "([^"]+)"`)
+)
+
+// ErrNoReport is returned when the container output does not contain any flyway info output
+var ErrNoReport = errors.New("no flyway info output found")
+
+// MigrationInfo describes a single migration, as listed by the flyway info command
+type MigrationInfo struct {
+	Category    string
+	Version     string
+	Description string
+	Type        string
+	InstalledOn time.Time // zero if the migration has not been installed
+	State       string
+	Checksum    *int // nil with the default text output, see FlywayContainer.Report
+}
+
+// Report represents the state of the database migrations, as reported by the flyway info command
+type Report struct {
+	SchemaVersion string // empty if the schema is empty
+	// HistorySchema is the schema holding the schema history table. With the text output, it is only known if
+	// flyway logged it, e.g. when running migrate before info.
+	HistorySchema string
+	Migrations    []MigrationInfo
+}
+
+// Applied returns the migrations which have been applied successfully, in the order reported by flyway
+func (r *Report) Applied() []MigrationInfo {
+	return r.withState(MigrationStateSuccess)
+}
+
+// Pending returns the migrations which have not been applied yet, in the order reported by flyway
+func (r *Report) Pending() []MigrationInfo {
+	return r.withState(MigrationStatePending)
+}
+
+func (r *Report) withState(state string) []MigrationInfo {
+	var migrations []MigrationInfo
+	for _, migration := range r.Migrations {
+		if migration.State == state {
+			migrations = append(migrations, migration)
+		}
+	}
+	return migrations
+}
+
+// Report returns the migrations report printed by the flyway info command run by the container, the command is
+// executed on demand in exec mode. The checksums of the migrations are only reported with OutputTypeJSON or in
+// exec mode, the text output does not include them.
+func (c *FlywayContainer) Report(ctx context.Context) (*Report, error) {
+	if c.exec {
+		info, err := c.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return info.report()
+	}
+
+	if c.outputType == OutputTypeJSON {
+		output, err := c.Output(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if output.Info == nil {
+			return nil, ErrNoReport
+		}
+		return output.Info.report()
+	}
+
+	logs, err := c.Logs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer logs.Close()
+
+	return parseInfo(logs)
+}
+
+// parseInfo parses the last info table printed by flyway, columns are matched by their header so the
+// order of the columns does not matter
+func parseInfo(r io.Reader) (*Report, error) {
+	var (
+		report        *Report
+		current       *Report
+		header        []string
+		borders       int
+		historySchema string
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if match := infoHistoryTableRegexp.FindStringSubmatch(line); match != nil {
+			historySchema = match[1]
+		} else if match := infoCurrentSchemaRegexp.FindStringSubmatch(line); match != nil && historySchema == "" {
+			historySchema = match[1]
+		}
+
+		switch {
+		case strings.HasPrefix(line, infoSchemaVersionPrefix):
+			version := strings.TrimSpace(strings.TrimPrefix(line, infoSchemaVersionPrefix))
+			if version == infoEmptySchemaVersion {
+				version = ""
+			}
+			current = &Report{SchemaVersion: version}
+			header = nil
+			borders = 0
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "+-"):
+			borders++
+			if borders == 3 { // top, below header, bottom
+				report = current
+				current = nil
+			}
+		case strings.HasPrefix(line, "|"):
+			cells := splitInfoRow(line)
+			if header == nil {
+				header = cells
+				continue
+			}
+			if len(cells) != len(header) {
+				continue // e.g. the "No migrations found" row
+			}
+
+			migration, err := parseInfoRow(header, cells)
+			if err != nil {
+				return nil, err
+			}
+			current.Migrations = append(current.Migrations, migration)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read flyway info output: %w", err)
+	}
+
+	if report == nil {
+		return nil, ErrNoReport
+	}
+	report.HistorySchema = historySchema
+	return report, nil
+}
+
+func splitInfoRow(line string) []string {
+	cells := strings.Split(strings.Trim(line, "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+func parseInfoRow(header, cells []string) (MigrationInfo, error) {
+	var migration MigrationInfo
+	for i, column := range header {
+		value := cells[i]
+		switch column {
+		case "Category":
+			migration.Category = value
+		case "Version":
+			migration.Version = value
+		case "Description":
+			migration.Description = value
+		case "Type":
+			migration.Type = value
+		case "Installed On":
+			if value == "" {
+				continue
+			}
+			installedOn, err := time.ParseInLocation(infoInstalledOnLayout, value, time.UTC)
+			if err != nil {
+				return migration, fmt.Errorf("invalid installed on date %q for migration %q: %w", value, migration.Version, err)
+			}
+			migration.InstalledOn = installedOn
+		case "State":
+			migration.State = value
+		}
+	}
+	return migration, nil
+}
@@ -0,0 +1,80 @@
+package flyway
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const infoOutput = `Flyway Community Edition 10.15.0 by Redgate
+
+See release notes here: https://rd.gt/416ObMi
+Database: jdbc:postgresql://pgdb:5432/test_db (PostgreSQL 16.3)
+Successfully validated 3 migrations (execution time 00:00.017s)
+Creating Schema History table "public"."schema_version" ...
+Current version of schema "public": << Empty Schema >>
+Migrating schema "public" to version "1.1 - create table stuff"
+Migrating schema "public" to version "1.2 - alter table stuff"
+Successfully applied 2 migrations to schema "public", now at version v1.2 (execution time 00:00.031s)
+Database: jdbc:postgresql://pgdb:5432/test_db (PostgreSQL 16.3)
+Schema version: 1.2
+
++-----------+---------+--------------------+------+---------------------+---------+----------+
+| Category  | Version | Description        | Type | Installed On        | State   | Undoable |
++-----------+---------+--------------------+------+---------------------+---------+----------+
+| Versioned | 1.1     | create table stuff | SQL  | 2024-07-01 09:21:53 | Success | No       |
+| Versioned | 1.2     | alter table stuff  | SQL  | 2024-07-01 09:21:54 | Success | No       |
+| Versioned | 1.3     | drop table stuff   | SQL  |                     | Pending | No       |
++-----------+---------+--------------------+------+---------------------+---------+----------+
+
+`
+
+func TestFlyway_parseInfo(t *testing.T) {
+	report, err := parseInfo(strings.NewReader(infoOutput))
+	require.NoError(t, err)
+
+	require.Equal(t, "1.2", report.SchemaVersion)
+	require.Equal(t, "public", report.HistorySchema)
+	require.Len(t, report.Migrations, 3)
+	require.Equal(t, MigrationInfo{
+		Category:    "Versioned",
+		Version:     "1.1",
+		Description: "create table stuff",
+		Type:        "SQL",
+		InstalledOn: time.Date(2024, 7, 1, 9, 21, 53, 0, time.UTC),
+		State:       MigrationStateSuccess,
+	}, report.Migrations[0])
+
+	applied := report.Applied()
+	require.Len(t, applied, 2)
+	require.Equal(t, "1.2", applied[1].Version)
+
+	pending := report.Pending()
+	require.Len(t, pending, 1)
+	require.Equal(t, "1.3", pending[0].Version)
+	require.True(t, pending[0].InstalledOn.IsZero())
+}
+
+func TestFlyway_parseInfoNoMigrations(t *testing.T) {
+	output := `Schema version: << Empty Schema >>
+
++----------+---------+-------------+------+--------------+-------+----------+
+| Category | Version | Description | Type | Installed On | State | Undoable |
++----------+---------+-------------+------+--------------+-------+----------+
+| No migrations found                                                        |
++----------+---------+-------------+------+--------------+-------+----------+
+`
+
+	report, err := parseInfo(strings.NewReader(output))
+	require.NoError(t, err)
+	require.Empty(t, report.SchemaVersion)
+	require.Empty(t, report.HistorySchema)
+	require.Empty(t, report.Migrations)
+}
+
+func TestFlyway_parseInfoMissing(t *testing.T) {
+	_, err := parseInfo(strings.NewReader("Successfully applied 2 migrations to schema \"public\""))
+	require.ErrorIs(t, err, ErrNoReport)
+}
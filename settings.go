@@ -0,0 +1,210 @@
+package flyway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const flywayEnvPrefix = "FLYWAY_"
+
+// settingType is the type of the value expected by a flyway setting
+type settingType int
+
+const (
+	stringSetting settingType = iota // a non-empty string
+	valueSetting                     // any string, possibly empty
+	boolSetting                      // true or false
+	intSetting                       // a non-negative integer
+	listSetting                      // a comma separated list of non-empty strings
+)
+
+func (t settingType) String() string {
+	switch t {
+	case stringSetting:
+		return "non-empty string"
+	case valueSetting:
+		return "string"
+	case boolSetting:
+		return "boolean"
+	case intSetting:
+		return "non-negative integer"
+	case listSetting:
+		return "comma separated list"
+	default:
+		return "unknown"
+	}
+}
+
+// flywaySettings is the registry of the flyway settings known by the module, mapping each environment variable to
+// the type of its value. Placeholders are registered by prefix, see lookupSetting. The other settings are rejected,
+// unless set with WithUncheckedSetting.
+var flywaySettings = map[string]settingType{
+	flywayEnvUserKey:                   stringSetting,
+	flywayEnvPasswordKey:               valueSetting,
+	flywayEnvUrlKey:                    stringSetting,
+	flywayEnvGroupKey:                  boolSetting,
+	flywayEnvTableKey:                  stringSetting,
+	flywayEnvConnectRetriesKey:         intSetting,
+	flywayEnvLocationsKey:              listSetting,
+	flywayEnvCleanDisabledKey:          boolSetting,
+	flywayEnvSchemasKey:                listSetting,
+	flywayEnvDefaultSchemaKey:          stringSetting,
+	flywayEnvCreateSchemasKey:          boolSetting,
+	flywayEnvTargetKey:                 stringSetting,
+	flywayEnvBaselineVersionKey:        stringSetting,
+	flywayEnvLicenseKeyKey:             stringSetting,
+	flywayEnvPlaceholderPrefixKey:      stringSetting,
+	flywayEnvPlaceholderSuffixKey:      stringSetting,
+	flywayEnvPlaceholderReplacementKey: boolSetting,
+
+	// the settings without a dedicated option, see WithSetting
+	"FLYWAY_BASELINE_DESCRIPTION":            stringSetting,
+	"FLYWAY_BASELINE_ON_MIGRATE":             boolSetting,
+	"FLYWAY_BATCH":                           boolSetting,
+	"FLYWAY_CALLBACKS":                       listSetting,
+	"FLYWAY_CHERRY_PICK":                     listSetting,
+	"FLYWAY_CONNECT_RETRIES_INTERVAL":        intSetting,
+	"FLYWAY_DETECT_ENCODING":                 boolSetting,
+	"FLYWAY_DRIVER":                          stringSetting,
+	"FLYWAY_ENCODING":                        stringSetting,
+	"FLYWAY_EXECUTE_IN_TRANSACTION":          boolSetting,
+	"FLYWAY_FAIL_ON_MISSING_LOCATIONS":       boolSetting,
+	"FLYWAY_IGNORE_MIGRATION_PATTERNS":       listSetting,
+	"FLYWAY_INIT_SQL":                        valueSetting,
+	"FLYWAY_INSTALLED_BY":                    stringSetting,
+	"FLYWAY_JAR_DIRS":                        listSetting,
+	"FLYWAY_LOCK_RETRY_COUNT":                intSetting,
+	"FLYWAY_MIXED":                           boolSetting,
+	"FLYWAY_OUT_OF_ORDER":                    boolSetting,
+	"FLYWAY_OUTPUT_QUERY_RESULTS":            boolSetting,
+	"FLYWAY_PLACEHOLDER_SEPARATOR":           stringSetting,
+	"FLYWAY_REPEATABLE_SQL_MIGRATION_PREFIX": stringSetting,
+	"FLYWAY_SKIP_DEFAULT_CALLBACKS":          boolSetting,
+	"FLYWAY_SKIP_EXECUTING_MIGRATIONS":       boolSetting,
+	"FLYWAY_SQL_MIGRATION_PREFIX":            stringSetting,
+	"FLYWAY_SQL_MIGRATION_SEPARATOR":         stringSetting,
+	"FLYWAY_SQL_MIGRATION_SUFFIXES":          listSetting,
+	"FLYWAY_VALIDATE_MIGRATION_NAMING":       boolSetting,
+	"FLYWAY_VALIDATE_ON_MIGRATE":             boolSetting,
+}
+
+func lookupSetting(key string) (settingType, bool) {
+	if strings.HasPrefix(key, flywayEnvPlaceholdersPrefix) {
+		return valueSetting, placeholderEnvKeyRegexp.MatchString(key)
+	}
+
+	settingType, ok := flywaySettings[key]
+	return settingType, ok
+}
+
+// WithSetting sets a flyway setting by its environment variable, e.g. FLYWAY_BASELINE_ON_MIGRATE, for the
+// settings without a dedicated option. Unknown settings and mistyped values are rejected.
+func WithSetting(key, value string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		if err := checkSettingKey(key); err != nil {
+			return err
+		}
+		return withSetting(key, value)(req)
+	}
+}
+
+// WithUncheckedSetting sets a flyway setting by its environment variable without checking it, e.g. a setting of a
+// newer flyway version than the module knows. A mistyped key is silently ignored by flyway.
+func WithUncheckedSetting(key, value string) Option {
+	return func(o *options) error {
+		if err := checkSettingKey(key); err != nil {
+			return err
+		}
+
+		if o.uncheckedSettings == nil {
+			o.uncheckedSettings = map[string]string{}
+		}
+		o.uncheckedSettings[key] = value
+		return nil
+	}
+}
+
+func checkSettingKey(key string) error {
+	if !strings.HasPrefix(key, flywayEnvPrefix) || key == flywayEnvPrefix {
+		return fmt.Errorf("invalid setting %q: flyway environment variables start with %s", key, flywayEnvPrefix)
+	}
+	return nil
+}
+
+// withSetting sets a flyway setting, the value is formatted according to its go type and
+// rejected if it does not match the type expected by the setting
+func withSetting(key string, value any) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		var formatted string
+		switch v := value.(type) {
+		case string:
+			formatted = v
+		case bool:
+			formatted = strconv.FormatBool(v)
+		case int:
+			formatted = strconv.Itoa(v)
+		case []string:
+			formatted = strings.Join(v, ",")
+		default:
+			return fmt.Errorf("invalid setting %s: unsupported value type %T", key, value)
+		}
+
+		if err := validateSetting(key, formatted); err != nil {
+			return err
+		}
+
+		return testcontainers.WithEnv(map[string]string{
+			key: formatted,
+		})(req)
+	}
+}
+
+// parseSettings validates the flyway settings of the request, including the ones set without the module options,
+// but the unchecked ones
+func parseSettings(env map[string]string, unchecked map[string]string) error {
+	for key, value := range env {
+		if _, ok := unchecked[key]; ok || !strings.HasPrefix(key, flywayEnvPrefix) {
+			continue
+		}
+		if err := validateSetting(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSetting(key, value string) error {
+	settingType, ok := lookupSetting(key)
+	if !ok {
+		if strings.HasPrefix(key, flywayEnvPlaceholdersPrefix) {
+			return fmt.Errorf("invalid placeholder %q: placeholder keys must only contain letters, digits and underscores",
+				strings.ToLower(strings.TrimPrefix(key, flywayEnvPlaceholdersPrefix)))
+		}
+		return fmt.Errorf("unknown setting: environment variable %s is not supported by the flyway module, please use flyway.WithUncheckedSetting()", key)
+	}
+
+	valid := true
+	switch settingType {
+	case stringSetting:
+		valid = value != ""
+	case valueSetting:
+	case boolSetting:
+		_, err := strconv.ParseBool(value)
+		valid = err == nil
+	case intSetting:
+		i, err := strconv.Atoi(value)
+		valid = err == nil && i >= 0
+	case listSetting:
+		for _, item := range strings.Split(value, ",") {
+			valid = valid && strings.TrimSpace(item) != ""
+		}
+	}
+
+	if !valid {
+		return fmt.Errorf("invalid setting: environment variable %s expects a %s, got %q", key, settingType, value)
+	}
+	return nil
+}
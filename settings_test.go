@@ -0,0 +1,121 @@
+package flyway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestFlyway_withSetting(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithGroup(false)(&req))
+	require.NoError(t, WithConnectRetries(5)(&req))
+	require.NoError(t, WithSchemas("public", "stuff")(&req))
+	require.NoError(t, WithTable("my_schema_history")(&req))
+	require.NoError(t, WithBaselineVersion("1.1")(&req))
+	require.NoError(t, WithLicenseKey("FL01-KEY")(&req))
+
+	require.Equal(t, map[string]string{
+		"FLYWAY_GROUP":            "false",
+		"FLYWAY_CONNECT_RETRIES":  "5",
+		"FLYWAY_SCHEMAS":          "public,stuff",
+		"FLYWAY_TABLE":            "my_schema_history",
+		"FLYWAY_BASELINE_VERSION": "1.1",
+		"FLYWAY_LICENSE_KEY":      "FL01-KEY",
+	}, req.Env)
+	require.NoError(t, parseSettings(req.Env, nil))
+}
+
+func TestFlyway_withSettingInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value any
+	}{
+		{
+			name:  "unsupported value type",
+			key:   flywayEnvConnectRetriesKey,
+			value: 3.5,
+		},
+		{
+			name:  "unknown setting",
+			key:   "FLYWAY_UNKNOWN",
+			value: "stuff",
+		},
+		{
+			name:  "mistyped boolean",
+			key:   flywayEnvGroupKey,
+			value: "my_group",
+		},
+		{
+			name:  "mistyped integer",
+			key:   flywayEnvConnectRetriesKey,
+			value: true,
+		},
+		{
+			name:  "negative integer",
+			key:   flywayEnvConnectRetriesKey,
+			value: -1,
+		},
+		{
+			name:  "empty string",
+			key:   flywayEnvTableKey,
+			value: "",
+		},
+		{
+			name:  "empty list item",
+			key:   flywayEnvSchemasKey,
+			value: []string{"public", ""},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			req := testcontainers.GenericContainerRequest{}
+			require.Error(tt, withSetting(testCase.key, testCase.value)(&req), "expected error")
+			require.Empty(tt, req.Env, "expected no setting")
+		})
+	}
+}
+
+func TestFlyway_withSettingRegistered(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithSetting("FLYWAY_BASELINE_ON_MIGRATE", "true")(&req))
+	require.NoError(t, WithSetting("FLYWAY_OUT_OF_ORDER", "true")(&req))
+
+	require.Equal(t, map[string]string{
+		"FLYWAY_BASELINE_ON_MIGRATE": "true",
+		"FLYWAY_OUT_OF_ORDER":        "true",
+	}, req.Env)
+	require.NoError(t, parseSettings(req.Env, nil))
+}
+
+func TestFlyway_withSettingInvalidKey(t *testing.T) {
+	req := testcontainers.GenericContainerRequest{}
+	require.Error(t, WithSetting("BASELINE_ON_MIGRATE", "true")(&req), "expected the flyway prefix to be required")
+	require.Error(t, WithSetting("FLYWAY_", "true")(&req), "expected a setting name")
+	require.Error(t, WithSetting("FLYWAY_GROUP", "my_group")(&req), "expected registered settings to be checked")
+	require.ErrorContains(t, WithSetting("FLYWAY_BASELINE_ON_MIGRTE", "true")(&req), "unknown setting")
+	require.Empty(t, req.Env, "expected no setting")
+}
+
+func TestFlyway_withUncheckedSetting(t *testing.T) {
+	settings := defaultOptions()
+	require.NoError(t, WithUncheckedSetting("FLYWAY_NEW_SETTING", "stuff")(&settings))
+	require.Error(t, WithUncheckedSetting("NEW_SETTING", "stuff")(&settings), "expected the flyway prefix to be required")
+	require.Equal(t, map[string]string{"FLYWAY_NEW_SETTING": "stuff"}, settings.uncheckedSettings)
+
+	env := map[string]string{"FLYWAY_NEW_SETTING": "stuff"}
+	require.Error(t, parseSettings(env, nil))
+	require.NoError(t, parseSettings(env, settings.uncheckedSettings), "expected unchecked settings to be skipped")
+}
+
+func TestFlyway_parseSettingsInvalid(t *testing.T) {
+	require.Error(t, parseSettings(map[string]string{"FLYWAY_GROUP": "my_group"}, nil))
+	require.ErrorContains(t, parseSettings(map[string]string{"FLYWAY_UNKNOWN": "stuff"}, nil), "unknown setting")
+	require.NoError(t, parseSettings(map[string]string{"JAVA_ARGS": "-Xmx512m"}, nil), "expected non flyway variables to be ignored")
+}
@@ -0,0 +1,496 @@
+package flyway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcnetwork "github.com/testcontainers/testcontainers-go/network"
+)
+
+// ErrNoNetworkAlias is returned when a database container can't be reached by the flyway container, because it
+// has no alias on a user defined network
+var ErrNoNetworkAlias = errors.New("the database container has no alias on a user defined network")
+
+// DatabaseTarget is a database migrated by the flyway container
+type DatabaseTarget interface {
+	// Connection resolves the settings flyway needs to connect to the database
+	Connection(ctx context.Context) (*Connection, error)
+}
+
+// Connection holds the settings flyway needs to connect to a database target
+type Connection struct {
+	URL      string
+	User     string
+	Password string
+	// Network is the docker network the flyway container joins to reach the database, empty if none
+	Network string
+	// Settings holds the additional flyway settings the database needs, keyed by environment variable
+	Settings map[string]string
+}
+
+// WithDatabaseTarget sets the database migrated by the container. The connection settings provided explicitly,
+// e.g. with WithUser, take precedence over the ones resolved from the target.
+func WithDatabaseTarget(target DatabaseTarget) Option {
+	return func(o *options) error {
+		if target == nil {
+			return errors.New("missing database target")
+		}
+
+		o.target = target
+		return nil
+	}
+}
+
+// WithPostgres sets a container of the testcontainers postgres module as the database migrated by the container
+func WithPostgres(container testcontainers.Container) Option {
+	return WithDatabaseTarget(PostgresTarget(container))
+}
+
+// PostgresTarget returns the database target of a container of the testcontainers postgres module, the
+// credentials and database are read from the container environment
+func PostgresTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    postgresEngine,
+	}
+}
+
+// WithMySQL sets a container of the testcontainers mysql module as the database migrated by the container
+func WithMySQL(container testcontainers.Container) Option {
+	return WithDatabaseTarget(MySQLTarget(container))
+}
+
+// MySQLTarget returns the database target of a container of the testcontainers mysql module, the credentials and
+// database are read from the container environment. The public key retrieval required by the default MySQL 8
+// authentication, without TLS, is allowed.
+func MySQLTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    mysqlEngine,
+	}
+}
+
+// WithMariaDB sets a container of the testcontainers mariadb module as the database migrated by the container
+func WithMariaDB(container testcontainers.Container) Option {
+	return WithDatabaseTarget(MariaDBTarget(container))
+}
+
+// MariaDBTarget returns the database target of a container of the testcontainers mariadb module, the credentials
+// and database are read from the container environment
+func MariaDBTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    mariadbEngine,
+	}
+}
+
+// WithMSSQL sets a container of the testcontainers mssql module as the database migrated by the container
+func WithMSSQL(container testcontainers.Container) Option {
+	return WithDatabaseTarget(MSSQLTarget(container))
+}
+
+// MSSQLTarget returns the database target of a container of the testcontainers mssql module. The migrations are
+// applied to the master database by the sa user, whose password is read from the container environment. The
+// self-signed certificate of the server is trusted.
+func MSSQLTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    mssqlEngine,
+	}
+}
+
+// WithOracle sets a container of the gvenzl/oracle-free image as the database migrated by the container
+func WithOracle(container testcontainers.Container) Option {
+	return WithDatabaseTarget(OracleTarget(container))
+}
+
+// OracleTarget returns the database target of a container of the gvenzl/oracle-free image. The migrations are
+// applied by the application user when one is configured, by the system user otherwise, to the pluggable
+// database set by ORACLE_DATABASE, FREEPDB1 by default.
+func OracleTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    oracleEngine,
+	}
+}
+
+// WithCockroachDB sets a container of the cockroachdb/cockroach image as the database migrated by the container
+func WithCockroachDB(container testcontainers.Container) Option {
+	return WithDatabaseTarget(CockroachDBTarget(container))
+}
+
+// CockroachDBTarget returns the database target of an insecure CockroachDB node, e.g. started by the testcontainers
+// cockroachdb module. The credentials and database are read from the container environment, defaulting to the
+// root user and the defaultdb database.
+func CockroachDBTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    cockroachdbEngine,
+	}
+}
+
+// WithClickHouse sets a container of the testcontainers clickhouse module as the database migrated by the container
+func WithClickHouse(container testcontainers.Container) Option {
+	return WithDatabaseTarget(ClickHouseTarget(container))
+}
+
+// ClickHouseTarget returns the database target of a container of the testcontainers clickhouse module, the
+// credentials and database are read from the container environment. ClickHouse has no transactions, so the
+// migrations are not grouped.
+func ClickHouseTarget(container testcontainers.Container) DatabaseTarget {
+	return &containerTarget{
+		container: container,
+		engine:    clickhouseEngine,
+	}
+}
+
+// withConnection fills in the connection settings resolved from the target, unless they are provided explicitly,
+// and attaches the flyway container to the network of the target. A target which has no alias on a user defined
+// network is attached to one first, the returned network is then detached when the flyway container terminates.
+func withConnection(ctx context.Context, req *testcontainers.GenericContainerRequest, target DatabaseTarget) (*targetNetwork, error) {
+	conn, err := target.Connection(ctx)
+
+	var attached *targetNetwork
+	if attacher, ok := target.(networkAttacher); ok && errors.Is(err, ErrNoNetworkAlias) {
+		attached, err = attacher.attachNetwork(ctx, req.Networks)
+		if err == nil {
+			conn, err = target.Connection(ctx)
+		}
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to resolve database target: %w", err), attached.detach(ctx))
+	}
+
+	settings := map[string]string{
+		flywayEnvUrlKey:      conn.URL,
+		flywayEnvUserKey:     conn.User,
+		flywayEnvPasswordKey: conn.Password,
+	}
+	for key, value := range conn.Settings {
+		settings[key] = value
+	}
+
+	for key, value := range settings {
+		if _, ok := req.Env[key]; ok {
+			continue
+		}
+		if err := withSetting(key, value)(req); err != nil {
+			return nil, errors.Join(fmt.Errorf("invalid database target: %w", err), attached.detach(ctx))
+		}
+	}
+
+	if conn.Network != "" && !slices.Contains(req.Networks, conn.Network) {
+		req.Networks = append(req.Networks, conn.Network)
+	}
+	return attached, nil
+}
+
+// networkAttacher is implemented by the targets which can join a user defined network when they have no alias on
+// one
+type networkAttacher interface {
+	// attachNetwork attaches the target to the first of the given networks, or to a new one when none is given
+	attachNetwork(ctx context.Context, networks []string) (*targetNetwork, error)
+}
+
+// targetNetwork is a network the module attached a database container to
+type targetNetwork struct {
+	name        string
+	containerID string
+	// created is the network created for the database container, nil if an existing one was reused
+	created *testcontainers.DockerNetwork
+}
+
+// detach disconnects the database container from the network and removes the network if it was created for it.
+// It is a no-op on a nil network.
+func (n *targetNetwork) detach(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detach database container from network %s: %w", n.name, err)
+	}
+	defer cli.Close()
+
+	// the database container may have been terminated already
+	if err := cli.NetworkDisconnect(ctx, n.name, n.containerID, true); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to detach database container from network %s: %w", n.name, err)
+	}
+
+	return n.remove(ctx)
+}
+
+// remove removes the network if it was created for the database container
+func (n *targetNetwork) remove(ctx context.Context) error {
+	if n.created == nil {
+		return nil
+	}
+
+	if err := n.created.Remove(ctx); err != nil {
+		return fmt.Errorf("failed to remove network %s: %w", n.name, err)
+	}
+	return nil
+}
+
+// engine describes how to connect to the databases of an engine running in a container
+type engine struct {
+	name string
+	port string
+	// credentials returns the user, password and database configured by the container environment
+	credentials func(env map[string]string) (string, string, string)
+	url         func(host, port, database string) string
+	// settings holds the additional flyway settings the engine needs
+	settings map[string]string
+}
+
+var postgresEngine = engine{
+	name: "postgres",
+	port: "5432",
+	credentials: func(env map[string]string) (string, string, string) {
+		user := valueOrDefault(env["POSTGRES_USER"], "postgres")
+		return user, env["POSTGRES_PASSWORD"], valueOrDefault(env["POSTGRES_DB"], user)
+	},
+	url: func(host, port, database string) string {
+		return PostgresURL(host, port, database).String()
+	},
+}
+
+var mysqlEngine = engine{
+	name:        "mysql",
+	port:        "3306",
+	credentials: mysqlCredentials("MYSQL_"),
+	url: func(host, port, database string) string {
+		return MySQLURL(host, port, database).WithParam("allowPublicKeyRetrieval", "true").String()
+	},
+}
+
+var mariadbEngine = engine{
+	name: "mariadb",
+	port: "3306",
+	credentials: func(env map[string]string) (string, string, string) {
+		// older mariadb images only support the MYSQL_ variables
+		user, password, database := mysqlCredentials("MARIADB_")(env)
+		if env["MARIADB_USER"] == "" && env["MARIADB_ROOT_PASSWORD"] == "" {
+			user, password, database = mysqlCredentials("MYSQL_")(env)
+		}
+		return user, password, database
+	},
+	url: func(host, port, database string) string {
+		return MariaDBURL(host, port, database).String()
+	},
+}
+
+var mssqlEngine = engine{
+	name: "mssql",
+	port: "1433",
+	credentials: func(env map[string]string) (string, string, string) {
+		// SA_PASSWORD is deprecated, but still supported by the image
+		return "sa", valueOrDefault(env["MSSQL_SA_PASSWORD"], env["SA_PASSWORD"]), "master"
+	},
+	url: func(host, port, database string) string {
+		return SQLServerURL(host, port, database).
+			WithParam("encrypt", "true").
+			WithParam("trustServerCertificate", "true").
+			String()
+	},
+}
+
+var oracleEngine = engine{
+	name: "oracle",
+	port: "1521",
+	credentials: func(env map[string]string) (string, string, string) {
+		database := valueOrDefault(env["ORACLE_DATABASE"], "FREEPDB1")
+		if user := env["APP_USER"]; user != "" {
+			return user, env["APP_USER_PASSWORD"], database
+		}
+		return "system", env["ORACLE_PASSWORD"], database
+	},
+	url: func(host, port, database string) string {
+		return OracleURL(host, port, database).String()
+	},
+}
+
+var cockroachdbEngine = engine{
+	name: "cockroachdb",
+	port: "26257",
+	credentials: func(env map[string]string) (string, string, string) {
+		return valueOrDefault(env["COCKROACH_USER"], "root"), env["COCKROACH_PASSWORD"], valueOrDefault(env["COCKROACH_DATABASE"], "defaultdb")
+	},
+	url: func(host, port, database string) string {
+		// flyway connects to cockroachdb with the postgres driver
+		return PostgresURL(host, port, database).WithParam("sslmode", "disable").String()
+	},
+}
+
+var clickhouseEngine = engine{
+	name: "clickhouse",
+	port: "8123",
+	credentials: func(env map[string]string) (string, string, string) {
+		return valueOrDefault(env["CLICKHOUSE_USER"], "default"), env["CLICKHOUSE_PASSWORD"], valueOrDefault(env["CLICKHOUSE_DB"], "default")
+	},
+	url: func(host, port, database string) string {
+		return ClickHouseURL(host, port, database).String()
+	},
+	settings: map[string]string{
+		flywayEnvGroupKey: "false",
+	},
+}
+
+// mysqlCredentials returns the credentials of the mysql and mariadb images, where the root user only has a
+// root password
+func mysqlCredentials(prefix string) func(env map[string]string) (string, string, string) {
+	return func(env map[string]string) (string, string, string) {
+		if user := env[prefix+"USER"]; user != "" {
+			return user, env[prefix+"PASSWORD"], env[prefix+"DATABASE"]
+		}
+		return "root", env[prefix+"ROOT_PASSWORD"], env[prefix+"DATABASE"]
+	}
+}
+
+// containerTarget is a database running in a container, reached through its alias on a user defined network
+type containerTarget struct {
+	container testcontainers.Container
+	engine    engine
+}
+
+// Connection implements DatabaseTarget
+func (t *containerTarget) Connection(ctx context.Context) (*Connection, error) {
+	if t.container == nil {
+		return nil, fmt.Errorf("missing %s container", t.engine.name)
+	}
+
+	inspect, err := t.container.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s container: %w", t.engine.name, err)
+	}
+
+	if inspect.Config == nil || inspect.NetworkSettings == nil {
+		return nil, fmt.Errorf("failed to inspect %s container: missing configuration", t.engine.name)
+	}
+
+	networkName, alias, err := networkAlias(inspect.ID, inspect.NetworkSettings.Networks)
+	if err != nil {
+		return nil, fmt.Errorf("%w: please attach the %s container to a network, e.g. with network.WithNetwork()", err, t.engine.name)
+	}
+
+	user, password, database := t.engine.credentials(containerEnv(inspect.Config.Env))
+
+	return &Connection{
+		URL:      t.engine.url(alias, t.engine.port, database),
+		User:     user,
+		Password: password,
+		Network:  networkName,
+		Settings: t.engine.settings,
+	}, nil
+}
+
+// attachNetwork implements networkAttacher, the container is reachable by an alias made of the name of its engine
+// and its short id, e.g. postgres-0123456789ab, so it does not clash with the other containers of the network
+func (t *containerTarget) attachNetwork(ctx context.Context, networks []string) (*targetNetwork, error) {
+	inspect, err := t.container.Inspect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s container: %w", t.engine.name, err)
+	}
+
+	attached := &targetNetwork{
+		containerID: inspect.ID,
+	}
+
+	var endpoints map[string]*network.EndpointSettings
+	if inspect.NetworkSettings != nil {
+		endpoints = inspect.NetworkSettings.Networks
+	}
+
+	if name := reusableNetwork(networks, endpoints); name != "" {
+		attached.name = name
+	} else {
+		created, err := tcnetwork.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create network for %s container: %w", t.engine.name, err)
+		}
+		attached.name = created.Name
+		attached.created = created
+	}
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to attach %s container to network %s: %w", t.engine.name, attached.name, err), attached.remove(ctx))
+	}
+	defer cli.Close()
+
+	err = cli.NetworkConnect(ctx, attached.name, attached.containerID, &network.EndpointSettings{
+		Aliases: []string{targetAlias(t.engine.name, attached.containerID)},
+	})
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to attach %s container to network %s: %w", t.engine.name, attached.name, err), attached.remove(ctx))
+	}
+	return attached, nil
+}
+
+// reusableNetwork returns the first of the given networks, unless the container is already one of its endpoints, in
+// which case its aliases can't be changed without disconnecting it. An empty name means a new network is needed.
+func reusableNetwork(networks []string, endpoints map[string]*network.EndpointSettings) string {
+	if len(networks) == 0 {
+		return ""
+	}
+	if _, ok := endpoints[networks[0]]; ok {
+		return ""
+	}
+	return networks[0]
+}
+
+// targetAlias returns the alias of a database container on the network the module attached it to
+func targetAlias(engineName, containerID string) string {
+	const shortIDLength = 12
+	if len(containerID) > shortIDLength {
+		containerID = containerID[:shortIDLength]
+	}
+	return engineName + "-" + containerID
+}
+
+// networkAlias returns the first user defined network, by name, on which the container has an alias other than
+// its id
+func networkAlias(id string, networks map[string]*network.EndpointSettings) (string, string, error) {
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if name == "bridge" || name == "host" || name == "none" || networks[name] == nil {
+			continue
+		}
+		for _, alias := range networks[name].Aliases {
+			if alias != "" && !strings.HasPrefix(id, alias) {
+				return name, alias, nil
+			}
+		}
+	}
+	return "", "", ErrNoNetworkAlias
+}
+
+func containerEnv(env []string) map[string]string {
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			vars[key] = value
+		}
+	}
+	return vars
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,269 @@
+package flyway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// inspectedContainer is a container which only supports being inspected
+type inspectedContainer struct {
+	testcontainers.Container
+	inspect *types.ContainerJSON
+}
+
+func (c *inspectedContainer) Inspect(context.Context) (*types.ContainerJSON, error) {
+	return c.inspect, nil
+}
+
+func newInspectedContainer(env []string, networks map[string]*network.EndpointSettings) *inspectedContainer {
+	return &inspectedContainer{
+		inspect: &types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: "0123456789abcdef"},
+			Config:            &container.Config{Env: env},
+			NetworkSettings:   &types.NetworkSettings{Networks: networks},
+		},
+	}
+}
+
+func TestFlyway_postgresTarget(t *testing.T) {
+	target := PostgresTarget(newInspectedContainer(
+		[]string{"POSTGRES_USER=test-user", "POSTGRES_PASSWORD=test-password", "POSTGRES_DB=test_db"},
+		map[string]*network.EndpointSettings{
+			"bridge":  {},
+			"test-nw": {Aliases: []string{"0123456789ab", "pgdb"}},
+		},
+	))
+
+	conn, err := target.Connection(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &Connection{
+		URL:      "jdbc:postgresql://pgdb:5432/test_db",
+		User:     "test-user",
+		Password: "test-password",
+		Network:  "test-nw",
+	}, conn)
+}
+
+func TestFlyway_postgresTargetDefaults(t *testing.T) {
+	target := PostgresTarget(newInspectedContainer(
+		[]string{"POSTGRES_PASSWORD=test-password"},
+		map[string]*network.EndpointSettings{
+			"test-nw": {Aliases: []string{"pgdb"}},
+		},
+	))
+
+	conn, err := target.Connection(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "jdbc:postgresql://pgdb:5432/postgres", conn.URL)
+	require.Equal(t, "postgres", conn.User)
+}
+
+func TestFlyway_postgresTargetNoNetwork(t *testing.T) {
+	target := PostgresTarget(newInspectedContainer(
+		[]string{"POSTGRES_PASSWORD=test-password"},
+		map[string]*network.EndpointSettings{
+			"bridge": {Aliases: []string{"pgdb"}},
+		},
+	))
+
+	_, err := target.Connection(context.Background())
+	require.ErrorIs(t, err, ErrNoNetworkAlias)
+}
+
+func TestFlyway_reusableNetwork(t *testing.T) {
+	endpoints := map[string]*network.EndpointSettings{
+		"bridge":  {},
+		"test-nw": {Aliases: []string{"0123456789ab"}},
+	}
+
+	require.Empty(t, reusableNetwork(nil, endpoints), "expected a new network when none is given")
+	require.Equal(t, "other-nw", reusableNetwork([]string{"other-nw", "test-nw"}, endpoints))
+	require.Empty(t, reusableNetwork([]string{"test-nw"}, endpoints), "expected a new network when the container is already an endpoint")
+}
+
+func TestFlyway_targetAlias(t *testing.T) {
+	require.Equal(t, "postgres-0123456789ab", targetAlias("postgres", "0123456789abcdef0123456789abcdef"))
+	require.Equal(t, "mysql-0123", targetAlias("mysql", "0123"))
+}
+
+func TestFlyway_withConnection(t *testing.T) {
+	target := PostgresTarget(newInspectedContainer(
+		[]string{"POSTGRES_USER=test-user", "POSTGRES_PASSWORD=test-password"},
+		map[string]*network.EndpointSettings{
+			"test-nw": {Aliases: []string{"pgdb"}},
+		},
+	))
+
+	req := testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithUser("other-user")(&req))
+	_, err := withConnection(context.Background(), &req, target)
+	require.NoError(t, err)
+
+	require.Equal(t, "jdbc:postgresql://pgdb:5432/test-user", req.Env[flywayEnvUrlKey])
+	require.Equal(t, "other-user", req.Env[flywayEnvUserKey], "expected explicit settings to take precedence")
+	require.Equal(t, "test-password", req.Env[flywayEnvPasswordKey])
+	require.Equal(t, []string{"test-nw"}, req.Networks)
+}
+
+func TestFlyway_mysqlTargets(t *testing.T) {
+	networks := map[string]*network.EndpointSettings{
+		"test-nw": {Aliases: []string{"db"}},
+	}
+
+	tests := []struct {
+		name     string
+		target   DatabaseTarget
+		expected *Connection
+	}{
+		{
+			name:   "mysql user",
+			target: MySQLTarget(newInspectedContainer([]string{"MYSQL_USER=test", "MYSQL_PASSWORD=secret", "MYSQL_DATABASE=stuff"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:mysql://db:3306/stuff?allowPublicKeyRetrieval=true",
+				User:     "test",
+				Password: "secret",
+				Network:  "test-nw",
+			},
+		},
+		{
+			name:   "mysql root",
+			target: MySQLTarget(newInspectedContainer([]string{"MYSQL_ROOT_PASSWORD=secret", "MYSQL_DATABASE=stuff"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:mysql://db:3306/stuff?allowPublicKeyRetrieval=true",
+				User:     "root",
+				Password: "secret",
+				Network:  "test-nw",
+			},
+		},
+		{
+			name:   "mariadb user",
+			target: MariaDBTarget(newInspectedContainer([]string{"MARIADB_USER=test", "MARIADB_PASSWORD=secret", "MARIADB_DATABASE=stuff"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:mariadb://db:3306/stuff",
+				User:     "test",
+				Password: "secret",
+				Network:  "test-nw",
+			},
+		},
+		{
+			name:   "mariadb legacy root",
+			target: MariaDBTarget(newInspectedContainer([]string{"MYSQL_ROOT_PASSWORD=secret", "MYSQL_DATABASE=stuff"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:mariadb://db:3306/stuff",
+				User:     "root",
+				Password: "secret",
+				Network:  "test-nw",
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			conn, err := testCase.target.Connection(context.Background())
+			require.NoError(tt, err)
+			require.Equal(tt, testCase.expected, conn)
+		})
+	}
+}
+
+func TestFlyway_otherTargets(t *testing.T) {
+	networks := map[string]*network.EndpointSettings{
+		"test-nw": {Aliases: []string{"db"}},
+	}
+
+	tests := []struct {
+		name     string
+		target   DatabaseTarget
+		expected *Connection
+	}{
+		{
+			name:   "mssql",
+			target: MSSQLTarget(newInspectedContainer([]string{"ACCEPT_EULA=Y", "MSSQL_SA_PASSWORD=Str0ng!Secret"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:sqlserver://db:1433;databaseName=master;encrypt=true;trustServerCertificate=true",
+				User:     "sa",
+				Password: "Str0ng!Secret",
+				Network:  "test-nw",
+			},
+		},
+		{
+			name:   "oracle app user",
+			target: OracleTarget(newInspectedContainer([]string{"ORACLE_PASSWORD=secret", "APP_USER=test", "APP_USER_PASSWORD=app-secret"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:oracle:thin:@//db:1521/FREEPDB1",
+				User:     "test",
+				Password: "app-secret",
+				Network:  "test-nw",
+			},
+		},
+		{
+			name:   "oracle system",
+			target: OracleTarget(newInspectedContainer([]string{"ORACLE_PASSWORD=secret", "ORACLE_DATABASE=STUFF"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:oracle:thin:@//db:1521/STUFF",
+				User:     "system",
+				Password: "secret",
+				Network:  "test-nw",
+			},
+		},
+		{
+			name:   "cockroachdb insecure",
+			target: CockroachDBTarget(newInspectedContainer(nil, networks)),
+			expected: &Connection{
+				URL:     "jdbc:postgresql://db:26257/defaultdb?sslmode=disable",
+				User:    "root",
+				Network: "test-nw",
+			},
+		},
+		{
+			name:   "clickhouse",
+			target: ClickHouseTarget(newInspectedContainer([]string{"CLICKHOUSE_USER=test", "CLICKHOUSE_PASSWORD=secret", "CLICKHOUSE_DB=stuff"}, networks)),
+			expected: &Connection{
+				URL:      "jdbc:clickhouse://db:8123/stuff",
+				User:     "test",
+				Password: "secret",
+				Network:  "test-nw",
+				Settings: map[string]string{flywayEnvGroupKey: "false"},
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(tt *testing.T) {
+			testCase := testCase
+
+			conn, err := testCase.target.Connection(context.Background())
+			require.NoError(tt, err)
+			require.Equal(tt, testCase.expected, conn)
+		})
+	}
+}
+
+func TestFlyway_withConnectionSettings(t *testing.T) {
+	target := ClickHouseTarget(newInspectedContainer(
+		[]string{"CLICKHOUSE_USER=test", "CLICKHOUSE_PASSWORD=secret"},
+		map[string]*network.EndpointSettings{
+			"test-nw": {Aliases: []string{"clickhouse"}},
+		},
+	))
+
+	req := testcontainers.GenericContainerRequest{}
+	_, err := withConnection(context.Background(), &req, target)
+	require.NoError(t, err)
+	require.Equal(t, "false", req.Env[flywayEnvGroupKey])
+
+	req = testcontainers.GenericContainerRequest{}
+	require.NoError(t, WithGroup(true)(&req))
+	_, err = withConnection(context.Background(), &req, target)
+	require.NoError(t, err)
+	require.Equal(t, "true", req.Env[flywayEnvGroupKey], "expected explicit settings to take precedence")
+}
@@ -0,0 +1 @@
+ALTER TABLE stuff ADD COLUMN created_timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
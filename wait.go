@@ -0,0 +1,86 @@
+package flyway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const defaultPollInterval = 100 * time.Millisecond
+
+// exitStrategy waits for the flyway container to exit. It returns as soon as flyway exits with a non-zero code,
+// with the failure found in the logs, otherwise it checks once that the commands logged their success.
+type exitStrategy struct {
+	timeout      time.Duration
+	pollInterval time.Duration
+	// commands are the commands whose success is checked in the logs, none when the json output is checked instead
+	commands []Command
+}
+
+// WaitUntilReady implements wait.Strategy
+func (s *exitStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	exitCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	for {
+		state, err := target.State(exitCtx)
+		if err != nil {
+			return fmt.Errorf("failed to get container state: %w", err)
+		}
+
+		if !state.Running {
+			if state.ExitCode != 0 {
+				return newMigrationError(ctx, target, state.ExitCode)
+			}
+			return checkCommandsLogged(ctx, target, s.commands)
+		}
+
+		select {
+		case <-exitCtx.Done():
+			return fmt.Errorf("flyway did not exit within %s: %w", s.timeout, exitCtx.Err())
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// checkCommandsLogged checks the logs hold a success line per command logging one, as many times as the command
+// is run
+func checkCommandsLogged(ctx context.Context, target wait.StrategyTarget, cmds []Command) error {
+	occurrences := map[Command]int{}
+	for _, cmd := range cmds {
+		if commands[cmd].logged != "" {
+			occurrences[cmd]++
+		}
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	logs, err := target.Logs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get container logs: %w", err)
+	}
+	defer logs.Close()
+
+	content, err := io.ReadAll(logs)
+	if err != nil {
+		return fmt.Errorf("failed to read container logs: %w", err)
+	}
+
+	for _, cmd := range cmds {
+		count, ok := occurrences[cmd]
+		if !ok {
+			continue
+		}
+		delete(occurrences, cmd)
+
+		if logged := regexp.MustCompile(commands[cmd].logged).FindAllIndex(content, -1); len(logged) < count {
+			return fmt.Errorf("flyway exited without logging the success of %s, expected %d time(s) but found %d", cmd, count, len(logged))
+		}
+	}
+	return nil
+}
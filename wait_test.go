@@ -0,0 +1,72 @@
+package flyway
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// exitingTarget is a container which exits after being polled a number of times
+type exitingTarget struct {
+	wait.StrategyTarget
+	polls    int
+	exitCode int
+	logs     string
+}
+
+func (t *exitingTarget) State(context.Context) (*types.ContainerState, error) {
+	t.polls--
+	return &types.ContainerState{Running: t.polls > 0, ExitCode: t.exitCode}, nil
+}
+
+func (t *exitingTarget) Logs(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(t.logs)), nil
+}
+
+func TestFlyway_exitStrategy(t *testing.T) {
+	strategy := &exitStrategy{
+		timeout:      time.Second,
+		pollInterval: time.Millisecond,
+		commands:     []Command{CommandMigrate, CommandInfo, CommandInfo},
+	}
+
+	err := strategy.WaitUntilReady(context.Background(), &exitingTarget{polls: 3, logs: infoOutput + infoOutput})
+	require.NoError(t, err)
+
+	err = strategy.WaitUntilReady(context.Background(), &exitingTarget{polls: 3, logs: infoOutput})
+	require.EqualError(t, err, "flyway exited without logging the success of info, expected 2 time(s) but found 1")
+}
+
+func TestFlyway_exitStrategyFailure(t *testing.T) {
+	strategy := &exitStrategy{
+		timeout:      time.Minute,
+		pollInterval: time.Millisecond,
+		commands:     []Command{CommandMigrate, CommandInfo},
+	}
+
+	start := time.Now()
+	err := strategy.WaitUntilReady(context.Background(), &exitingTarget{polls: 3, exitCode: 1, logs: failedMigrationOutput})
+	require.Less(t, time.Since(start), time.Second, "expected the failure to be reported once flyway exited")
+
+	var migrationErr *MigrationError
+	require.ErrorAs(t, err, &migrationErr)
+	require.Equal(t, 1, migrationErr.ExitCode)
+	require.Equal(t, "V1.2__alter_table_stuff.sql", migrationErr.Script)
+}
+
+func TestFlyway_exitStrategyTimeout(t *testing.T) {
+	strategy := &exitStrategy{
+		timeout:      10 * time.Millisecond,
+		pollInterval: time.Millisecond,
+	}
+
+	err := strategy.WaitUntilReady(context.Background(), &exitingTarget{polls: 1000})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}